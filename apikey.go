@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	apikeyUsecase "github.com/phantomnat/go-clean-architecture/apikey/usecase"
+	"github.com/phantomnat/go-clean-architecture/config"
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+const apiKeyUsage = "Usage: apikey issue -name name -scopes scope,... [-author id] [-ttl duration] | revoke id | list"
+
+// listPageSize is the number of keys read from the usecase at once
+const listPageSize = 100
+
+// cliPrincipal is the caller of the commands, whoever runs them manages the keys
+var cliPrincipal = domain.Principal{Subject: "cli", Roles: []string{domain.RoleAdmin}}
+
+// runAPIKey implements `apikey issue|revoke|list`
+func runAPIKey(cfg config.AppConfig, args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, apiKeyUsage)
+		os.Exit(2)
+	}
+	if cfg.Database.Driver == config.DriverMemory {
+		logrus.Fatal("the memory driver keeps nothing once the command exits, there is no key to manage")
+	}
+
+	var run func(ctx context.Context, ku domain.APIKeyUsecase) error
+	switch args[0] {
+	case "issue":
+		fs := flag.NewFlagSet("apikey issue", flag.ExitOnError)
+		name := fs.String("name", "", "name of the client the key is issued to")
+		scopes := fs.String("scopes", "", "comma separated scopes: articles:read, articles:write, authors:manage")
+		authorID := fs.Int64("author", 0, "id of the author the client acts as, every author by default")
+		ttl := fs.Duration("ttl", 0, "lifetime of the key, it never expires by default")
+		fs.Parse(args[1:])
+
+		k := domain.APIKey{Name: *name, AuthorID: *authorID}
+		for _, s := range strings.Split(*scopes, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				k.Scopes = append(k.Scopes, domain.APIKeyScope(s))
+			}
+		}
+		if *ttl > 0 {
+			expiresAt := time.Now().Add(*ttl)
+			k.ExpiresAt = &expiresAt
+		}
+		run = func(ctx context.Context, ku domain.APIKeyUsecase) error {
+			key, err := ku.Issue(ctx, &k)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "issued API key %d (%s), it is only shown once:\n", k.ID, k.Prefix)
+			fmt.Println(key)
+			return nil
+		}
+	case "revoke":
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, apiKeyUsage)
+			os.Exit(2)
+		}
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			logrus.Fatalf("invalid API key id: %s", args[1])
+		}
+		run = func(ctx context.Context, ku domain.APIKeyUsecase) error {
+			if err := ku.Revoke(ctx, id); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "revoked API key %d\n", id)
+			return nil
+		}
+	case "list":
+		run = func(ctx context.Context, ku domain.APIKeyUsecase) error {
+			return printAPIKeys(ctx, ku, os.Stdout, time.Now())
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown apikey command: %s\n", args[0])
+		os.Exit(2)
+	}
+
+	s := openStorage(cfg.Database, false)
+	defer s.close()
+	ku := apikeyUsecase.NewAPIKeyUseCase(s.apiKeyRepo, s.authorRepo, cfg.Timeouts.Usecase)
+
+	ctx := domain.ContextWithPrincipal(context.Background(), cliPrincipal)
+	if err := run(ctx, ku); err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+// printAPIKeys writes every key as a row of a table, along with its status at now
+func printAPIKeys(ctx context.Context, ku domain.APIKeyUsecase, w io.Writer, now time.Time) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tPREFIX\tNAME\tSCOPES\tAUTHOR\tEXPIRES\tLAST USED\tSTATUS")
+
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return "-"
+		}
+		return t.Format(time.RFC3339)
+	}
+	cursor := ""
+	for {
+		list, nextCursor, err := ku.Fetch(ctx, cursor, listPageSize)
+		if err != nil {
+			return err
+		}
+		for _, k := range list {
+			scopes := make([]string, len(k.Scopes))
+			for i, s := range k.Scopes {
+				scopes[i] = string(s)
+			}
+			author := "-"
+			if k.AuthorID != 0 {
+				author = strconv.FormatInt(k.AuthorID, 10)
+			}
+			status := "active"
+			switch {
+			case k.RevokedAt != nil:
+				status = "revoked"
+			case !k.IsActive(now):
+				status = "expired"
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", k.ID, k.Prefix, k.Name, strings.Join(scopes, ","),
+				author, formatTime(k.ExpiresAt), formatTime(k.LastUsedAt), status)
+		}
+		if nextCursor == "" {
+			return tw.Flush()
+		}
+		cursor = nextCursor
+	}
+}
@@ -0,0 +1,150 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader carries the API key of the machine clients
+const APIKeyHeader = "X-API-Key"
+
+// ResponseError represents the response error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// IssuedAPIKey is the response of an issued key, the only one with the key itself
+type IssuedAPIKey struct {
+	domain.APIKey
+	Key string `json:"key"`
+}
+
+// APIKeyHandler represents the http handler of the API key administration
+type APIKeyHandler struct {
+	APIKeyUsecase domain.APIKeyUsecase
+}
+
+func NewAPIKeyHttpHandler(e *gin.Engine, ku domain.APIKeyUsecase) {
+	handler := &APIKeyHandler{
+		APIKeyUsecase: ku,
+	}
+
+	e.GET("/admin/api-keys", handler.FetchAPIKey)
+	e.POST("/admin/api-keys", handler.Issue)
+	e.DELETE("/admin/api-keys/:id", handler.Revoke)
+}
+
+// Middleware authenticates the API key of the requests with the X-API-Key header and puts its principal in their
+// context. The requests without key are left to the other credentials, a request with an invalid key is answered 401.
+func Middleware(ku domain.APIKeyUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(APIKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		p, err := ku.Authenticate(ctx, key)
+		if err == domain.ErrInvalidAPIKey {
+			logging.FromContext(ctx).Info(err)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ResponseError{Message: err.Error()})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(getStatusCode(ctx, err), ResponseError{Message: err.Error()})
+			return
+		}
+
+		ctx = domain.ContextWithPrincipal(ctx, p)
+		ctx = logging.NewContext(ctx, logging.FromContext(ctx).WithField("subject", p.Subject))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// FetchAPIKey will fetch the API keys, the revoked and expired ones included
+func (a *APIKeyHandler) FetchAPIKey(c *gin.Context) {
+	n := c.Query("num")
+	num, _ := strconv.Atoi(n)
+
+	cursor := c.Query("cursor")
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	list, nextCursor, err := a.APIKeyUsecase.Fetch(ctx, cursor, int64(num))
+	if err != nil {
+		c.AbortWithStatusJSON(getStatusCode(ctx, err), ResponseError{Message: err.Error()})
+		return
+	}
+
+	c.Header("X-Cursor", nextCursor)
+	c.JSON(http.StatusOK, list)
+}
+
+// Issue will issue the API key of the request body, the key is only given in the response
+func (a *APIKeyHandler) Issue(c *gin.Context) {
+	var k domain.APIKey
+	if err := c.ShouldBindJSON(&k); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	key, err := a.APIKeyUsecase.Issue(ctx, &k)
+	if err != nil {
+		c.AbortWithStatusJSON(getStatusCode(ctx, err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, IssuedAPIKey{APIKey: k, Key: key})
+}
+
+// Revoke will revoke the API key by given id
+func (a *APIKeyHandler) Revoke(c *gin.Context) {
+	i, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, ResponseError{Message: domain.ErrNotFound.Error()})
+		return
+	}
+
+	id := int64(i)
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	err = a.APIKeyUsecase.Revoke(ctx, id)
+	if err != nil {
+		c.AbortWithStatusJSON(getStatusCode(ctx, err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func getStatusCode(ctx context.Context, err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	logging.FromContext(ctx).Error(err)
+	switch err {
+	case domain.ErrInternalServer:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrAlreadyExist:
+		return http.StatusConflict
+	case domain.ErrBadParamInput:
+		return http.StatusBadRequest
+	case domain.ErrForbidden:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
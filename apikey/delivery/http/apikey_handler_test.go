@@ -0,0 +1,136 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apikeyHttp "github.com/phantomnat/go-clean-architecture/apikey/delivery/http"
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/domain/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newRouter serves a route answering the subject of the principal behind the middleware
+func newRouter(ku domain.APIKeyUsecase) *gin.Engine {
+	e := gin.New()
+	e.Use(apikeyHttp.Middleware(ku))
+	e.GET("/whoami", func(c *gin.Context) {
+		p, _ := domain.PrincipalFromContext(c.Request.Context())
+		c.String(http.StatusOK, p.Subject)
+	})
+	return e
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("valid key", func(t *testing.T) {
+		mockUCase := new(mocks.APIKeyUsecase)
+		p := domain.Principal{Subject: "apikey:gca_1234", APIKeyID: 1}
+		mockUCase.On("Authenticate", mock.Anything, "gca_1234abcd").Return(p, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+		req.Header.Set(apikeyHttp.APIKeyHeader, "gca_1234abcd")
+		rec := httptest.NewRecorder()
+		newRouter(mockUCase).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "apikey:gca_1234", rec.Body.String())
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		mockUCase := new(mocks.APIKeyUsecase)
+		mockUCase.On("Authenticate", mock.Anything, "gca_revoked").Return(domain.Principal{}, domain.ErrInvalidAPIKey).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+		req.Header.Set(apikeyHttp.APIKeyHeader, "gca_revoked")
+		rec := httptest.NewRecorder()
+		newRouter(mockUCase).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.JSONEq(t, `{"message":"invalid API key"}`, rec.Body.String())
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		mockUCase := new(mocks.APIKeyUsecase)
+
+		req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+		rec := httptest.NewRecorder()
+		newRouter(mockUCase).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Body.String())
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestIssue(t *testing.T) {
+	mockUCase := new(mocks.APIKeyUsecase)
+	mockUCase.On("Issue", mock.Anything, mock.AnythingOfType("*domain.APIKey")).Run(func(args mock.Arguments) {
+		k := args.Get(1).(*domain.APIKey)
+		k.ID = 7
+		k.Prefix = "gca_1234abcd"
+		k.Hash = "hash"
+	}).Return("gca_1234abcdsecret", nil).Once()
+
+	e := gin.New()
+	apikeyHttp.NewAPIKeyHttpHandler(e, mockUCase)
+
+	body, err := json.Marshal(domain.APIKey{Name: "importer", Scopes: []domain.APIKeyScope{domain.ScopeArticlesWrite}})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/admin/api-keys", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var issued map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &issued))
+	assert.Equal(t, "gca_1234abcdsecret", issued["key"])
+	assert.Equal(t, "gca_1234abcd", issued["prefix"])
+	assert.NotContains(t, issued, "hash")
+	mockUCase.AssertExpectations(t)
+}
+
+func TestFetch(t *testing.T) {
+	mockUCase := new(mocks.APIKeyUsecase)
+	mockUCase.On("Fetch", mock.Anything, "", int64(0)).Return(nil, "", domain.ErrForbidden).Once()
+
+	e := gin.New()
+	apikeyHttp.NewAPIKeyHttpHandler(e, mockUCase)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/api-keys", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestRevoke(t *testing.T) {
+	mockUCase := new(mocks.APIKeyUsecase)
+	mockUCase.On("Revoke", mock.Anything, int64(7)).Return(nil).Once()
+	mockUCase.On("Revoke", mock.Anything, int64(8)).Return(domain.ErrNotFound).Once()
+
+	e := gin.New()
+	apikeyHttp.NewAPIKeyHttpHandler(e, mockUCase)
+
+	for id, code := range map[string]int{"7": http.StatusNoContent, "8": http.StatusNotFound, "x": http.StatusNotFound} {
+		req := httptest.NewRequest(http.MethodDelete, "/admin/api-keys/"+id, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, code, rec.Code, id)
+	}
+	mockUCase.AssertExpectations(t)
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+)
+
+// DecodeCursor will decode the cursor given by the user into the id of the last key of the previous page
+func DecodeCursor(encodedID string) (int64, error) {
+	byt, err := base64.StdEncoding.DecodeString(encodedID)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(string(byt), 10, 64)
+}
+
+// EncodeCursor will encode the id of the last key of a page into the cursor given to the user
+func EncodeCursor(id int64) string {
+	idString := strconv.FormatInt(id, 10)
+
+	return base64.StdEncoding.EncodeToString([]byte(idString))
+}
+
+// JoinScopes will encode the scopes into the value of the scopes column
+func JoinScopes(scopes []domain.APIKeyScope) string {
+	list := make([]string, len(scopes))
+	for i, s := range scopes {
+		list[i] = string(s)
+	}
+	return strings.Join(list, ",")
+}
+
+// SplitScopes will decode the value of the scopes column
+func SplitScopes(column string) []domain.APIKeyScope {
+	if column == "" {
+		return []domain.APIKeyScope{}
+	}
+	list := strings.Split(column, ",")
+	scopes := make([]domain.APIKeyScope, len(list))
+	for i, s := range list {
+		scopes[i] = domain.APIKeyScope(s)
+	}
+	return scopes
+}
+
+// NullableTime will give the value of a nullable DATETIME column, in UTC
+func NullableTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/apikey/repository"
+	"github.com/phantomnat/go-clean-architecture/domain"
+)
+
+type memoryAPIKeyRepo struct {
+	mu     sync.RWMutex
+	keys   map[int64]domain.APIKey
+	lastID int64
+}
+
+// NewMemoryAPIKeyRepository will create an object that represent the domain.APIKeyRepository interface.
+// The keys are kept in memory and are lost when the process exits.
+func NewMemoryAPIKeyRepository() domain.APIKeyRepository {
+	return &memoryAPIKeyRepo{
+		keys: make(map[int64]domain.APIKey),
+	}
+}
+
+// clone copies the key, the caller cannot change the stored one through its times or scopes
+func clone(k domain.APIKey) domain.APIKey {
+	k.Scopes = append([]domain.APIKeyScope{}, k.Scopes...)
+	for _, t := range []**time.Time{&k.ExpiresAt, &k.LastUsedAt, &k.RevokedAt} {
+		if *t != nil {
+			v := **t
+			*t = &v
+		}
+	}
+	return k
+}
+
+func (m *memoryAPIKeyRepo) Fetch(ctx context.Context, cursor string, num int64) (res []domain.APIKey, nextCursor string, err error) {
+	if num <= 0 {
+		return make([]domain.APIKey, 0), "", nil
+	}
+
+	decodedCursor, err := repository.DecodeCursor(cursor)
+	if err != nil && cursor != "" {
+		return nil, "", domain.ErrBadParamInput
+	}
+
+	m.mu.RLock()
+	res = make([]domain.APIKey, 0)
+	for _, k := range m.keys {
+		if k.ID > decodedCursor {
+			res = append(res, clone(k))
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID < res[j].ID
+	})
+	if int64(len(res)) > num {
+		res = res[:num]
+	}
+
+	if len(res) == int(num) {
+		nextCursor = repository.EncodeCursor(res[len(res)-1].ID)
+	}
+
+	return res, nextCursor, nil
+}
+
+func (m *memoryAPIKeyRepo) GetByID(ctx context.Context, id int64) (domain.APIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	k, ok := m.keys[id]
+	if !ok {
+		return domain.APIKey{}, domain.ErrNotFound
+	}
+	return clone(k), nil
+}
+
+func (m *memoryAPIKeyRepo) GetByHash(ctx context.Context, hash string) (domain.APIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, k := range m.keys {
+		if k.Hash == hash {
+			return clone(k), nil
+		}
+	}
+	return domain.APIKey{}, domain.ErrNotFound
+}
+
+func (m *memoryAPIKeyRepo) Store(ctx context.Context, k *domain.APIKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastID++
+	k.ID = m.lastID
+	m.keys[k.ID] = clone(*k)
+	return nil
+}
+
+func (m *memoryAPIKeyRepo) Revoke(ctx context.Context, id int64, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	k, ok := m.keys[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if k.RevokedAt == nil {
+		k.RevokedAt = &at
+		m.keys[id] = k
+	}
+	return nil
+}
+
+func (m *memoryAPIKeyRepo) UpdateLastUsed(ctx context.Context, id int64, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	k, ok := m.keys[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	k.LastUsedAt = &at
+	m.keys[id] = k
+	return nil
+}
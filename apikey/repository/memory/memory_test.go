@@ -0,0 +1,36 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/apikey/repository/memory"
+	"github.com/phantomnat/go-clean-architecture/apikey/repository/repositorytest"
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoredKeyIsCopied(t *testing.T) {
+	repo := memory.NewMemoryAPIKeyRepository()
+	expiresAt := time.Now().Add(time.Hour)
+	stored := expiresAt
+	k := domain.APIKey{Name: "importer", Hash: "hash", Scopes: []domain.APIKeyScope{domain.ScopeArticlesRead}, ExpiresAt: &stored}
+	require.NoError(t, repo.Store(context.TODO(), &k))
+
+	k.Scopes[0] = domain.ScopeAuthorsManage
+	*k.ExpiresAt = time.Time{}
+
+	res, err := repo.GetByID(context.TODO(), k.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.APIKeyScope{domain.ScopeArticlesRead}, res.Scopes)
+	assert.True(t, expiresAt.Equal(*res.ExpiresAt))
+}
+
+func TestContract(t *testing.T) {
+	repositorytest.RunAPIKeyRepositoryTests(t, func(t *testing.T) domain.APIKeyRepository {
+		return memory.NewMemoryAPIKeyRepository()
+	})
+}
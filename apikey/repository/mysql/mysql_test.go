@@ -0,0 +1,74 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/apikey/repository/mysql"
+	"github.com/phantomnat/go-clean-architecture/apikey/repository/repositorytest"
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetByHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "name", "prefix", "hash", "scopes", "author_id", "expires_at",
+		"last_used_at", "revoked_at", "created_at"}).
+		AddRow(1, "importer", "gca_abcdefgh", "hash", "articles:read,articles:write", 0, nil, now, nil, now)
+	query := "SELECT id, name, prefix, hash, scopes, author_id, expires_at, last_used_at, revoked_at, created_at FROM api_key WHERE hash=\\?"
+	mock.ExpectPrepare(query).ExpectQuery().WithArgs("hash").WillReturnRows(rows)
+
+	k, err := mysql.NewMysqlAPIKeyRepository(db).GetByHash(context.TODO(), "hash")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), k.ID)
+	assert.Equal(t, []domain.APIKeyScope{domain.ScopeArticlesRead, domain.ScopeArticlesWrite}, k.Scopes)
+	assert.Nil(t, k.ExpiresAt)
+	require.NotNil(t, k.LastUsedAt)
+	assert.True(t, now.Equal(*k.LastUsedAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRevoke(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	query := "UPDATE api_key SET revoked_at=COALESCE\\(revoked_at, \\?\\) WHERE id=\\?"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(now.UTC(), int64(12)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = mysql.NewMysqlAPIKeyRepository(db).Revoke(context.TODO(), 12, now)
+	assert.Equal(t, domain.ErrNotFound, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestContract runs the shared suite against a real MySQL server, e.g.
+// MYSQL_TEST_DSN="root:123456@tcp(localhost:3306)/article_test?parseTime=1&clientFoundRows=true"
+// The database must be migrated with `migrate up`, the api_key table is emptied before every test case.
+func TestContract(t *testing.T) {
+	dsn := os.Getenv("MYSQL_TEST_DSN")
+	if dsn == "" {
+		t.Skip("MYSQL_TEST_DSN is not set")
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	require.NoError(t, err)
+	defer db.Close()
+
+	repositorytest.RunAPIKeyRepositoryTests(t, func(t *testing.T) domain.APIKeyRepository {
+		_, err := db.Exec("TRUNCATE TABLE api_key")
+		require.NoError(t, err)
+		return mysql.NewMysqlAPIKeyRepository(db)
+	})
+}
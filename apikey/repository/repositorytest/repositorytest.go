@@ -0,0 +1,190 @@
+// Package repositorytest provides the conformance suite of domain.APIKeyRepository,
+// every storage backend runs it from its own tests to be verified the same way.
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// maxPages stops a pagination walk that never ends
+const maxPages = 100
+
+// APIKeyRepositoryFactory returns an empty repository, it is called once for every test case
+type APIKeyRepositoryFactory func(t *testing.T) domain.APIKeyRepository
+
+// RunAPIKeyRepositoryTests checks the contract shared by every domain.APIKeyRepository implementation
+func RunAPIKeyRepositoryTests(t *testing.T, newRepo APIKeyRepositoryFactory) {
+	t.Run("StoreAndGet", func(t *testing.T) { testStoreAndGet(t, newRepo(t)) })
+	t.Run("GetByHash", func(t *testing.T) { testGetByHash(t, newRepo(t)) })
+	t.Run("GetNotFound", func(t *testing.T) { testGetNotFound(t, newRepo(t)) })
+	t.Run("Revoke", func(t *testing.T) { testRevoke(t, newRepo(t)) })
+	t.Run("UpdateLastUsed", func(t *testing.T) { testUpdateLastUsed(t, newRepo(t)) })
+	t.Run("Paginate", func(t *testing.T) { testPaginate(t, newRepo(t)) })
+	t.Run("NonPositiveNum", func(t *testing.T) { testNonPositiveNum(t, newRepo(t)) })
+}
+
+// baseTime has no sub-second part, the DATETIME columns do not keep it
+var baseTime = time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+
+func store(t *testing.T, repo domain.APIKeyRepository, n int) []domain.APIKey {
+	list := make([]domain.APIKey, 0, n)
+	for i := 0; i < n; i++ {
+		k := domain.APIKey{
+			Name:      fmt.Sprintf("key %d", i),
+			Prefix:    fmt.Sprintf("gca_%08d", i),
+			Hash:      fmt.Sprintf("%064d", i),
+			Scopes:    []domain.APIKeyScope{domain.ScopeArticlesRead},
+			CreatedAt: baseTime,
+		}
+		require.NoError(t, repo.Store(context.TODO(), &k))
+		require.NotZero(t, k.ID, "Store must assign the id of the key")
+		list = append(list, k)
+	}
+	return list
+}
+
+func assertTime(t *testing.T, name string, expected, actual *time.Time) {
+	if expected == nil {
+		assert.Nil(t, actual, name)
+		return
+	}
+	if assert.NotNil(t, actual, name) {
+		assert.True(t, expected.Equal(*actual), "%s: expected %s, actual %s", name, expected, actual)
+	}
+}
+
+func assertAPIKey(t *testing.T, expected, actual domain.APIKey) {
+	assert.Equal(t, expected.ID, actual.ID)
+	assert.Equal(t, expected.Name, actual.Name)
+	assert.Equal(t, expected.Prefix, actual.Prefix)
+	assert.Equal(t, expected.Hash, actual.Hash)
+	assert.Equal(t, expected.Scopes, actual.Scopes)
+	assert.Equal(t, expected.AuthorID, actual.AuthorID)
+	assertTime(t, "expires_at", expected.ExpiresAt, actual.ExpiresAt)
+	assertTime(t, "last_used_at", expected.LastUsedAt, actual.LastUsedAt)
+	assertTime(t, "revoked_at", expected.RevokedAt, actual.RevokedAt)
+	assert.True(t, expected.CreatedAt.Equal(actual.CreatedAt), "created_at: expected %s, actual %s", expected.CreatedAt, actual.CreatedAt)
+}
+
+func testStoreAndGet(t *testing.T, repo domain.APIKeyRepository) {
+	expiresAt := baseTime.Add(24 * time.Hour)
+	k := domain.APIKey{
+		Name:      "importer",
+		Prefix:    "gca_abcdefgh",
+		Hash:      "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		Scopes:    []domain.APIKeyScope{domain.ScopeArticlesRead, domain.ScopeArticlesWrite},
+		AuthorID:  3,
+		ExpiresAt: &expiresAt,
+		CreatedAt: baseTime,
+	}
+	require.NoError(t, repo.Store(context.TODO(), &k))
+	require.NotZero(t, k.ID)
+
+	res, err := repo.GetByID(context.TODO(), k.ID)
+	require.NoError(t, err)
+	assertAPIKey(t, k, res)
+
+	other := store(t, repo, 1)[0]
+	assert.NotEqual(t, k.ID, other.ID)
+	res, err = repo.GetByID(context.TODO(), other.ID)
+	require.NoError(t, err)
+	assertAPIKey(t, other, res)
+}
+
+func testGetByHash(t *testing.T, repo domain.APIKeyRepository) {
+	list := store(t, repo, 3)
+
+	res, err := repo.GetByHash(context.TODO(), list[1].Hash)
+	require.NoError(t, err)
+	assertAPIKey(t, list[1], res)
+}
+
+func testGetNotFound(t *testing.T, repo domain.APIKeyRepository) {
+	list := store(t, repo, 1)
+
+	_, err := repo.GetByID(context.TODO(), list[0].ID+1)
+	assert.Equal(t, domain.ErrNotFound, err)
+	_, err = repo.GetByHash(context.TODO(), "unknown")
+	assert.Equal(t, domain.ErrNotFound, err)
+	assert.Equal(t, domain.ErrNotFound, repo.Revoke(context.TODO(), list[0].ID+1, baseTime))
+	assert.Equal(t, domain.ErrNotFound, repo.UpdateLastUsed(context.TODO(), list[0].ID+1, baseTime))
+}
+
+func testRevoke(t *testing.T, repo domain.APIKeyRepository) {
+	list := store(t, repo, 2)
+
+	revokedAt := baseTime.Add(time.Hour)
+	require.NoError(t, repo.Revoke(context.TODO(), list[0].ID, revokedAt))
+	// the first revocation is kept
+	require.NoError(t, repo.Revoke(context.TODO(), list[0].ID, revokedAt.Add(time.Hour)))
+
+	res, err := repo.GetByID(context.TODO(), list[0].ID)
+	require.NoError(t, err)
+	assertTime(t, "revoked_at", &revokedAt, res.RevokedAt)
+
+	res, err = repo.GetByID(context.TODO(), list[1].ID)
+	require.NoError(t, err)
+	assert.Nil(t, res.RevokedAt)
+}
+
+func testUpdateLastUsed(t *testing.T, repo domain.APIKeyRepository) {
+	list := store(t, repo, 1)
+
+	for _, usedAt := range []time.Time{baseTime.Add(time.Minute), baseTime.Add(time.Hour)} {
+		require.NoError(t, repo.UpdateLastUsed(context.TODO(), list[0].ID, usedAt))
+		res, err := repo.GetByID(context.TODO(), list[0].ID)
+		require.NoError(t, err)
+		assertTime(t, "last_used_at", &usedAt, res.LastUsedAt)
+	}
+}
+
+func testPaginate(t *testing.T, repo domain.APIKeyRepository) {
+	list := store(t, repo, 5)
+
+	var (
+		seen   []int64
+		cursor string
+	)
+	for page := 0; ; page++ {
+		require.True(t, page < maxPages, "the pagination does not end")
+		res, next, err := repo.Fetch(context.TODO(), cursor, 2)
+		require.NoError(t, err)
+		require.True(t, len(res) <= 2)
+		for _, k := range res {
+			seen = append(seen, k.ID)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	expected := make([]int64, len(list))
+	for i, k := range list {
+		expected[i] = k.ID
+	}
+	assert.Equal(t, expected, seen)
+
+	_, _, err := repo.Fetch(context.TODO(), "not a cursor", 2)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+// testNonPositiveNum asks for pages of no key, they must be empty whatever is stored
+func testNonPositiveNum(t *testing.T, repo domain.APIKeyRepository) {
+	store(t, repo, 2)
+
+	for _, num := range []int64{0, -1} {
+		res, next, err := repo.Fetch(context.TODO(), "", num)
+		require.NoError(t, err)
+		assert.Empty(t, res, "num %d", num)
+		assert.Empty(t, next)
+	}
+}
@@ -0,0 +1,167 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/apikey/repository"
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/logging"
+)
+
+const columns = `id, name, prefix, hash, scopes, author_id, expires_at, last_used_at, revoked_at, created_at`
+
+type sqliteAPIKeyRepo struct {
+	DB *sql.DB
+}
+
+// NewSqliteAPIKeyRepository will create an object that represent the domain.APIKeyRepository interface
+func NewSqliteAPIKeyRepository(db *sql.DB) domain.APIKeyRepository {
+	return &sqliteAPIKeyRepo{DB: db}
+}
+
+// scan reads the columns of a key from the row
+func scan(row interface{ Scan(...interface{}) error }) (k domain.APIKey, err error) {
+	var scopes string
+	err = row.Scan(
+		&k.ID,
+		&k.Name,
+		&k.Prefix,
+		&k.Hash,
+		&scopes,
+		&k.AuthorID,
+		&k.ExpiresAt,
+		&k.LastUsedAt,
+		&k.RevokedAt,
+		&k.CreatedAt,
+	)
+	k.Scopes = repository.SplitScopes(scopes)
+	return
+}
+
+func (m *sqliteAPIKeyRepo) getOne(ctx context.Context, query string, args ...interface{}) (domain.APIKey, error) {
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	defer stmt.Close()
+
+	res, err := scan(stmt.QueryRowContext(ctx, args...))
+	if err == sql.ErrNoRows {
+		return domain.APIKey{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	return res, nil
+}
+
+func (m *sqliteAPIKeyRepo) Fetch(ctx context.Context, cursor string, num int64) (res []domain.APIKey, nextCursor string, err error) {
+	// a negative LIMIT means no limit to SQLite
+	if num <= 0 {
+		return make([]domain.APIKey, 0), "", nil
+	}
+
+	query := `SELECT ` + columns + ` FROM api_key WHERE id > ? ORDER BY id LIMIT ?`
+
+	decodedCursor, err := repository.DecodeCursor(cursor)
+	if err != nil && cursor != "" {
+		return nil, "", domain.ErrBadParamInput
+	}
+
+	rows, err := m.DB.QueryContext(ctx, query, decodedCursor, num)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, "", err
+	}
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	res = make([]domain.APIKey, 0)
+	for rows.Next() {
+		k, err := scan(rows)
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, "", err
+		}
+		res = append(res, k)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(res) == int(num) {
+		nextCursor = repository.EncodeCursor(res[len(res)-1].ID)
+	}
+	return res, nextCursor, nil
+}
+
+func (m *sqliteAPIKeyRepo) GetByID(ctx context.Context, id int64) (domain.APIKey, error) {
+	return m.getOne(ctx, `SELECT `+columns+` FROM api_key WHERE id=?`, id)
+}
+
+func (m *sqliteAPIKeyRepo) GetByHash(ctx context.Context, hash string) (domain.APIKey, error) {
+	return m.getOne(ctx, `SELECT `+columns+` FROM api_key WHERE hash=?`, hash)
+}
+
+func (m *sqliteAPIKeyRepo) Store(ctx context.Context, k *domain.APIKey) (err error) {
+	query := `INSERT INTO api_key (name, prefix, hash, scopes, author_id, expires_at, last_used_at, revoked_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, k.Name, k.Prefix, k.Hash, repository.JoinScopes(k.Scopes), k.AuthorID,
+		repository.NullableTime(k.ExpiresAt), repository.NullableTime(k.LastUsedAt), repository.NullableTime(k.RevokedAt),
+		k.CreatedAt.UTC())
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	k.ID = lastID
+	return
+}
+
+func (m *sqliteAPIKeyRepo) Revoke(ctx context.Context, id int64, at time.Time) error {
+	return m.update(ctx, `UPDATE api_key SET revoked_at=COALESCE(revoked_at, ?) WHERE id=?`, at.UTC(), id)
+}
+
+func (m *sqliteAPIKeyRepo) UpdateLastUsed(ctx context.Context, id int64, at time.Time) error {
+	return m.update(ctx, `UPDATE api_key SET last_used_at=? WHERE id=?`, at.UTC(), id)
+}
+
+// update runs the update of a single key, it returns domain.ErrNotFound when the key does not exist
+func (m *sqliteAPIKeyRepo) update(ctx context.Context, query string, args ...interface{}) (err error) {
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect == 0 {
+		return domain.ErrNotFound
+	}
+	if affect != 1 {
+		return fmt.Errorf("Weird  Behaviour. Total Affected: %d", affect)
+	}
+	return nil
+}
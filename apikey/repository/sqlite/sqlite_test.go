@@ -0,0 +1,37 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/phantomnat/go-clean-architecture/apikey/repository/repositorytest"
+	"github.com/phantomnat/go-clean-architecture/apikey/repository/sqlite"
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/migration"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+// migrate creates the schema of the service in db
+func migrate(t *testing.T, db *sql.DB) {
+	m, err := migration.NewMigrator(db, "sqlite3")
+	require.NoError(t, err)
+	_, err = m.Up(context.TODO())
+	require.NoError(t, err)
+}
+
+func TestContract(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	migrate(t, db)
+
+	repositorytest.RunAPIKeyRepositoryTests(t, func(t *testing.T) domain.APIKeyRepository {
+		_, err := db.Exec("DELETE FROM api_key")
+		require.NoError(t, err)
+		return sqlite.NewSqliteAPIKeyRepository(db)
+	})
+}
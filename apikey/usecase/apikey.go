@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/logging"
+)
+
+// KeyPrefix starts every API key, it tells them apart from the other secrets, e.g. for the secret scanners
+const KeyPrefix = "gca_"
+
+const (
+	// prefixLength is the length of the start of the keys kept in clear
+	prefixLength = len(KeyPrefix) + 8
+	// keyBytes is the entropy of the keys, they are hashed without salt as they cannot be guessed
+	keyBytes = 32
+	// lastUsedResolution bounds the writes of the last use, a busy key is not written on every request
+	lastUsedResolution = time.Minute
+)
+
+type apiKeyUsecase struct {
+	// contextTimeout is a time.Duration, it is accessed atomically so it can be changed while serving
+	contextTimeout int64
+	apiKeyRepo     domain.APIKeyRepository
+	authorRepo     domain.AuthorRepository
+}
+
+var _ domain.APIKeyUsecase = &apiKeyUsecase{}
+
+// NewAPIKeyUseCase will create new apiKeyUsecase object representation of domain.APIKeyUsecase interface,
+// the authors of the keys are read from given repository
+func NewAPIKeyUseCase(apiKey domain.APIKeyRepository, author domain.AuthorRepository, timeout time.Duration) domain.APIKeyUsecase {
+	return &apiKeyUsecase{
+		apiKeyRepo:     apiKey,
+		authorRepo:     author,
+		contextTimeout: int64(timeout),
+	}
+}
+
+// SetContextTimeout changes the timeout of the next calls, the calls in flight keep theirs
+func (a *apiKeyUsecase) SetContextTimeout(timeout time.Duration) {
+	atomic.StoreInt64(&a.contextTimeout, int64(timeout))
+}
+
+func (a *apiKeyUsecase) timeout() time.Duration {
+	return time.Duration(atomic.LoadInt64(&a.contextTimeout))
+}
+
+// HashKey returns the hash of the key stored in place of the key
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// requireAdmin returns domain.ErrForbidden unless the principal of the context is an admin
+func requireAdmin(ctx context.Context) error {
+	if p, ok := domain.PrincipalFromContext(ctx); ok && p.HasRole(domain.RoleAdmin) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+func (a *apiKeyUsecase) Fetch(c context.Context, cursor string, num int64) (res []domain.APIKey, nextCursor string, err error) {
+	if err = requireAdmin(c); err != nil {
+		return nil, "", err
+	}
+	if num < 0 {
+		return nil, "", domain.ErrBadParamInput
+	}
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.timeout())
+	defer cancel()
+
+	return a.apiKeyRepo.Fetch(ctx, cursor, num)
+}
+
+func (a *apiKeyUsecase) Issue(c context.Context, k *domain.APIKey) (key string, err error) {
+	if err = requireAdmin(c); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	name := strings.TrimSpace(k.Name)
+	if name == "" || len(name) > 255 || !validScopes(k.Scopes) || (k.ExpiresAt != nil && !k.ExpiresAt.After(now)) {
+		return "", domain.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.timeout())
+	defer cancel()
+
+	if k.AuthorID != 0 {
+		_, err = a.authorRepo.GetByID(ctx, k.AuthorID)
+		if err == domain.ErrNotFound {
+			return "", domain.ErrBadParamInput
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	byt := make([]byte, keyBytes)
+	if _, err = rand.Read(byt); err != nil {
+		return "", err
+	}
+	key = KeyPrefix + base64.RawURLEncoding.EncodeToString(byt)
+
+	k.Name = name
+	k.Prefix = key[:prefixLength]
+	k.Hash = HashKey(key)
+	k.LastUsedAt = nil
+	k.RevokedAt = nil
+	k.CreatedAt = now
+	if err = a.apiKeyRepo.Store(ctx, k); err != nil {
+		return "", err
+	}
+	logging.FromContext(ctx).WithField("api_key_id", k.ID).Info("API key issued")
+	return key, nil
+}
+
+// validScopes tells whether the scopes are known, at least one and without duplicates
+func validScopes(scopes []domain.APIKeyScope) bool {
+	if len(scopes) == 0 {
+		return false
+	}
+	seen := make(map[domain.APIKeyScope]bool, len(scopes))
+	for _, s := range scopes {
+		if !s.IsValid() || seen[s] {
+			return false
+		}
+		seen[s] = true
+	}
+	return true
+}
+
+func (a *apiKeyUsecase) Revoke(c context.Context, id int64) error {
+	if err := requireAdmin(c); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.timeout())
+	defer cancel()
+
+	if err := a.apiKeyRepo.Revoke(ctx, id, time.Now()); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).WithField("api_key_id", id).Info("API key revoked")
+	return nil
+}
+
+// Authenticate returns the principal of the key. It acts as the author of the key,
+// or as an editor of every author when the key has none, within the scopes of the key.
+func (a *apiKeyUsecase) Authenticate(c context.Context, key string) (domain.Principal, error) {
+	if !strings.HasPrefix(key, KeyPrefix) {
+		return domain.Principal{}, domain.ErrInvalidAPIKey
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.timeout())
+	defer cancel()
+
+	k, err := a.apiKeyRepo.GetByHash(ctx, HashKey(key))
+	if err == domain.ErrNotFound {
+		return domain.Principal{}, domain.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return domain.Principal{}, err
+	}
+	now := time.Now()
+	if !k.IsActive(now) {
+		return domain.Principal{}, domain.ErrInvalidAPIKey
+	}
+
+	p := domain.Principal{
+		Subject:  "apikey:" + k.Prefix,
+		Roles:    []string{domain.RoleEditor},
+		APIKeyID: k.ID,
+		Scopes:   k.Scopes,
+	}
+	if k.AuthorID != 0 {
+		p.Author, err = a.authorRepo.GetByID(ctx, k.AuthorID)
+		if err == domain.ErrNotFound {
+			// the author was deleted after the key was issued
+			return domain.Principal{}, domain.ErrInvalidAPIKey
+		}
+		if err != nil {
+			return domain.Principal{}, err
+		}
+		p.Roles = []string{domain.RoleAuthor}
+	}
+
+	if k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) >= lastUsedResolution {
+		// the request is served all the same, only the tracking is late
+		if err := a.apiKeyRepo.UpdateLastUsed(ctx, k.ID, now); err != nil {
+			logging.FromContext(ctx).WithField("api_key_id", k.ID).Error(err)
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,136 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	apikeyMemory "github.com/phantomnat/go-clean-architecture/apikey/repository/memory"
+	"github.com/phantomnat/go-clean-architecture/apikey/usecase"
+	authorMemory "github.com/phantomnat/go-clean-architecture/author/repository/memory"
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var admin = domain.ContextWithPrincipal(context.TODO(), domain.Principal{Subject: "root", Roles: []string{domain.RoleAdmin}})
+
+func newUsecase(t *testing.T) (domain.APIKeyUsecase, domain.APIKeyRepository, domain.Author) {
+	keys := apikeyMemory.NewMemoryAPIKeyRepository()
+	authors := authorMemory.NewMemoryAuthorRepository()
+	author := domain.Author{Name: "Iron Man"}
+	require.NoError(t, authors.Store(context.TODO(), &author))
+	return usecase.NewAPIKeyUseCase(keys, authors, time.Second*2), keys, author
+}
+
+func TestIssueAndAuthenticate(t *testing.T) {
+	u, repo, author := newUsecase(t)
+
+	k := domain.APIKey{Name: " importer ", Scopes: []domain.APIKeyScope{domain.ScopeArticlesWrite}}
+	key, err := u.Issue(admin, &k)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, usecase.KeyPrefix))
+	assert.Equal(t, key[:len(k.Prefix)], k.Prefix)
+	assert.Equal(t, "importer", k.Name)
+
+	stored, err := repo.GetByID(context.TODO(), k.ID)
+	require.NoError(t, err)
+	assert.Equal(t, usecase.HashKey(key), stored.Hash)
+	assert.NotContains(t, stored.Hash, key[len(usecase.KeyPrefix):])
+	assert.Nil(t, stored.LastUsedAt)
+
+	p, err := u.Authenticate(context.TODO(), key)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Principal{
+		Subject:  "apikey:" + k.Prefix,
+		Roles:    []string{domain.RoleEditor},
+		APIKeyID: k.ID,
+		Scopes:   []domain.APIKeyScope{domain.ScopeArticlesWrite},
+	}, p)
+	stored, err = repo.GetByID(context.TODO(), k.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, stored.LastUsedAt)
+
+	t.Run("author", func(t *testing.T) {
+		k := domain.APIKey{Name: "blog", Scopes: []domain.APIKeyScope{domain.ScopeArticlesWrite}, AuthorID: author.ID}
+		key, err := u.Issue(admin, &k)
+		require.NoError(t, err)
+
+		p, err := u.Authenticate(context.TODO(), key)
+		require.NoError(t, err)
+		assert.Equal(t, author, p.Author)
+		assert.Equal(t, []string{domain.RoleAuthor}, p.Roles)
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		require.NoError(t, u.Revoke(admin, k.ID))
+		_, err := u.Authenticate(context.TODO(), key)
+		assert.Equal(t, domain.ErrInvalidAPIKey, err)
+		assert.Equal(t, domain.ErrNotFound, u.Revoke(admin, k.ID+100))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		expiresAt := time.Now().Add(-time.Second)
+		key := usecase.KeyPrefix + "expired"
+		require.NoError(t, repo.Store(context.TODO(), &domain.APIKey{
+			Name: "old", Hash: usecase.HashKey(key), Scopes: []domain.APIKeyScope{domain.ScopeArticlesRead}, ExpiresAt: &expiresAt,
+		}))
+		_, err := u.Authenticate(context.TODO(), key)
+		assert.Equal(t, domain.ErrInvalidAPIKey, err)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		for _, key := range []string{"", "gca_unknown", key + "x", "Bearer " + key} {
+			_, err := u.Authenticate(context.TODO(), key)
+			assert.Equal(t, domain.ErrInvalidAPIKey, err, key)
+		}
+	})
+}
+
+func TestIssueInvalid(t *testing.T) {
+	u, _, _ := newUsecase(t)
+	past := time.Now().Add(-time.Hour)
+
+	tests := map[string]domain.APIKey{
+		"no name":          {Scopes: []domain.APIKeyScope{domain.ScopeArticlesRead}},
+		"no scope":         {Name: "importer"},
+		"unknown scope":    {Name: "importer", Scopes: []domain.APIKeyScope{"articles:*"}},
+		"duplicated scope": {Name: "importer", Scopes: []domain.APIKeyScope{domain.ScopeArticlesRead, domain.ScopeArticlesRead}},
+		"expired":          {Name: "importer", Scopes: []domain.APIKeyScope{domain.ScopeArticlesRead}, ExpiresAt: &past},
+		"unknown author":   {Name: "importer", Scopes: []domain.APIKeyScope{domain.ScopeArticlesRead}, AuthorID: 42},
+	}
+	for name, k := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := u.Issue(admin, &k)
+			assert.Equal(t, domain.ErrBadParamInput, err)
+		})
+	}
+}
+
+func TestAdminOnly(t *testing.T) {
+	u, _, author := newUsecase(t)
+	editor := domain.ContextWithPrincipal(context.TODO(), domain.Principal{
+		Subject: "iman", Author: author, Roles: []string{domain.RoleEditor, domain.RoleAuthor},
+	})
+
+	for _, ctx := range []context.Context{context.TODO(), editor} {
+		_, err := u.Issue(ctx, &domain.APIKey{Name: "importer", Scopes: []domain.APIKeyScope{domain.ScopeArticlesRead}})
+		assert.Equal(t, domain.ErrForbidden, err)
+		_, _, err = u.Fetch(ctx, "", 0)
+		assert.Equal(t, domain.ErrForbidden, err)
+		assert.Equal(t, domain.ErrForbidden, u.Revoke(ctx, 1))
+	}
+
+	list, _, err := u.Fetch(admin, "", 0)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestFetchNegativeNum(t *testing.T) {
+	u, _, _ := newUsecase(t)
+
+	_, _, err := u.Fetch(admin, "", -1)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
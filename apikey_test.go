@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	apikeyUsecase "github.com/phantomnat/go-clean-architecture/apikey/usecase"
+	"github.com/phantomnat/go-clean-architecture/config"
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintAPIKeys(t *testing.T) {
+	s := openStorage(config.DatabaseConfig{Driver: config.DriverMemory}, false)
+	ku := apikeyUsecase.NewAPIKeyUseCase(s.apiKeyRepo, s.authorRepo, config.Default().Timeouts.Usecase)
+	ctx := domain.ContextWithPrincipal(context.TODO(), cliPrincipal)
+
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+	for i := 0; i < listPageSize+1; i++ {
+		k := domain.APIKey{Name: "importer", Scopes: []domain.APIKeyScope{domain.ScopeArticlesRead, domain.ScopeArticlesWrite}}
+		if i == 0 {
+			k.ExpiresAt = &expiresAt
+		}
+		_, err := ku.Issue(ctx, &k)
+		require.NoError(t, err)
+	}
+	require.NoError(t, ku.Revoke(ctx, 2))
+
+	var buf bytes.Buffer
+	require.NoError(t, printAPIKeys(ctx, ku, &buf, now.Add(2*time.Hour)))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, listPageSize+2)
+	assert.Contains(t, lines[0], "LAST USED")
+	assert.Contains(t, lines[1], "articles:read,articles:write")
+	assert.Equal(t, "expired", status(lines[1]))
+	assert.Equal(t, "revoked", status(lines[2]))
+	assert.Equal(t, "active", status(lines[3]))
+
+	assert.Equal(t, domain.ErrForbidden, printAPIKeys(context.TODO(), ku, &buf, now))
+}
+
+// status returns the last column of the row
+func status(row string) string {
+	f := strings.Fields(row)
+	return f[len(f)-1]
+}
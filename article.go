@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/phantomnat/go-clean-architecture/article/delivery/http"
+	"github.com/phantomnat/go-clean-architecture/config"
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// exportPageSize is the number of articles read from the usecase at once
+const exportPageSize = 100
+
+// runArticle implements `article export|import`
+func runArticle(cfg config.AppConfig, args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: article export [-o file] | import [-i file]")
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "export":
+		fs := flag.NewFlagSet("article export", flag.ExitOnError)
+		output := fs.String("o", "", "write the articles to the file instead of stdout")
+		fs.Parse(args[1:])
+
+		w := io.Writer(os.Stdout)
+		if *output != "" {
+			f, err := os.Create(*output)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		s := openStorage(cfg.Database, false)
+		defer s.close()
+		au, _ := newUsecases(s, false, cfg.Timeouts.Usecase)
+
+		n, err := exportArticles(context.Background(), au, w)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+		fmt.Fprintf(os.Stderr, "exported %d articles\n", n)
+	case "import":
+		fs := flag.NewFlagSet("article import", flag.ExitOnError)
+		input := fs.String("i", "", "read the articles from the file instead of stdin")
+		fs.Parse(args[1:])
+
+		r := io.Reader(os.Stdin)
+		if *input != "" {
+			f, err := os.Open(*input)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			defer f.Close()
+			r = f
+		}
+
+		if cfg.Database.Driver == config.DriverMemory {
+			logrus.Fatal("the memory driver keeps nothing once the command exits, there is nowhere to import")
+		}
+		s := openStorage(cfg.Database, false)
+		defer s.close()
+		au, auu := newUsecases(s, false, cfg.Timeouts.Usecase)
+
+		imported, skipped, err := importArticles(context.Background(), au, http.NewArticleValidator(auu), r)
+		fmt.Fprintf(os.Stderr, "imported %d articles, skipped %d\n", imported, skipped)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown article command: %s\n", args[0])
+		os.Exit(2)
+	}
+}
+
+// exportArticles writes every article as a line of JSON, ordered by creation time
+func exportArticles(ctx context.Context, au domain.ArticleUsecase, w io.Writer) (n int, err error) {
+	enc := json.NewEncoder(w)
+	cursor := ""
+	for {
+		list, nextCursor, _, err := au.Fetch(ctx, domain.ArticleFilter{}, cursor, exportPageSize,
+			domain.DirectionNext, domain.SortAsc)
+		if err != nil {
+			return n, err
+		}
+		for _, ar := range list {
+			if err := enc.Encode(ar); err != nil {
+				return n, err
+			}
+			n++
+		}
+		if nextCursor == "" {
+			return n, nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// importArticles stores the articles read as JSON values, one after the other, through the usecase.
+// The ids and timestamps of the input are not kept. The invalid articles and the ones with
+// an existing title are skipped, any other error stops the import.
+func importArticles(ctx context.Context, au domain.ArticleUsecase, v *http.ArticleValidator, r io.Reader) (
+	imported int, skipped int, err error) {
+
+	dec := json.NewDecoder(r)
+	for i := 1; ; i++ {
+		var ar domain.Article
+		err = dec.Decode(&ar)
+		if err == io.EOF {
+			return imported, skipped, nil
+		}
+		if err != nil {
+			return imported, skipped, fmt.Errorf("article %d: %v", i, err)
+		}
+		ar.ID = 0
+
+		fieldErrors, err := v.Validate(ctx, &ar)
+		if err != nil {
+			return imported, skipped, fmt.Errorf("article %d: %v", i, err)
+		}
+		if len(fieldErrors) > 0 {
+			messages := make([]string, 0, len(fieldErrors))
+			for _, fe := range fieldErrors {
+				messages = append(messages, fe.Message)
+			}
+			logrus.Warnf("skip article %d %q: %s", i, ar.Title, strings.Join(messages, ", "))
+			skipped++
+			continue
+		}
+
+		err = au.Store(ctx, &ar)
+		if err == domain.ErrAlreadyExist {
+			logrus.Warnf("skip article %d %q: %v", i, ar.Title, err)
+			skipped++
+			continue
+		}
+		if err != nil {
+			return imported, skipped, fmt.Errorf("article %d: %v", i, err)
+		}
+		imported++
+	}
+}
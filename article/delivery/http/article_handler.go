@@ -2,32 +2,46 @@ package http
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/phantomnat/go-clean-architecture/auth"
 	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/logging"
 
 	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
 )
 
 // ResponseError represents the response error struct
 type ResponseError struct {
-	Message string `json:"message"`
+	Message string       `json:"message"`
+	Errors  []FieldError `json:"errors,omitempty"`
 }
 
 // ArticleHandle represents the http handler for article
 type ArticleHandler struct {
 	ArticleUsecase domain.ArticleUsecase
+	Validator      *ArticleValidator
 }
 
-func NewArticleHttpHandler(e *gin.Engine, au domain.ArticleUsecase) {
+func NewArticleHttpHandler(e *gin.Engine, au domain.ArticleUsecase, v *ArticleValidator) {
 	handler := &ArticleHandler{
 		ArticleUsecase: au,
+		Validator:      v,
 	}
 
-	e.GET("/articles", handler.FetchArticle)
-	e.GET("/article/:id", handler.GetByID)
+	read := auth.RequireScope(domain.ScopeArticlesRead)
+	write := auth.RequireScope(domain.ScopeArticlesWrite)
+	e.GET("/articles", read, handler.FetchArticle)
+	e.GET("/articles/search", read, handler.SearchArticle)
+	e.GET("/article/:id", read, handler.GetByID)
+	e.POST("/articles", write, handler.Store)
+	e.PUT("/articles/:id", write, handler.Update)
+	e.DELETE("/articles/:id", write, handler.Delete)
 }
 
 // FetchArticle will fetch the article based on given params
@@ -36,16 +50,102 @@ func (a *ArticleHandler) FetchArticle(c *gin.Context) {
 	num, _ := strconv.Atoi(n)
 
 	cursor := c.Query("cursor")
+	direction := domain.Direction(c.Query("direction"))
+	order := domain.SortOrder(c.Query("order"))
 
-	ctx, cancel := context.WithCancel(c)
+	filter, err := parseArticleFilter(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
 	defer cancel()
 
-	listAr, nextCursor, err := a.ArticleUsecase.Fetch(ctx, cursor, int64(num))
+	listAr, nextCursor, prevCursor, err := a.ArticleUsecase.Fetch(ctx, filter, cursor, int64(num), direction, order)
 	if err != nil {
-		c.AbortWithStatusJSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		c.AbortWithStatusJSON(getStatusCode(ctx, err), ResponseError{Message: err.Error()})
 		return
 	}
 
+	links := make([]string, 0, 2)
+	if nextCursor != "" {
+		links = append(links, pageLink(c.Request.URL, nextCursor, domain.DirectionNext, "next"))
+	}
+	if prevCursor != "" {
+		links = append(links, pageLink(c.Request.URL, prevCursor, domain.DirectionPrev, "prev"))
+	}
+	if len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+
+	c.Header("X-Cursor", nextCursor)
+	c.JSON(http.StatusOK, listAr)
+}
+
+// parseArticleFilter reads the filter and sort params of the article list from the query string
+func parseArticleFilter(c *gin.Context) (filter domain.ArticleFilter, err error) {
+	if s := c.Query("author_id"); s != "" {
+		filter.AuthorID, err = strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return domain.ArticleFilter{}, fmt.Errorf("invalid author_id: %s", s)
+		}
+	}
+
+	timeParams := map[string]*time.Time{
+		"created_from": &filter.CreatedFrom,
+		"created_to":   &filter.CreatedTo,
+		"updated_from": &filter.UpdatedFrom,
+		"updated_to":   &filter.UpdatedTo,
+	}
+	for key, dst := range timeParams {
+		s := c.Query(key)
+		if s == "" {
+			continue
+		}
+		*dst, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			return domain.ArticleFilter{}, fmt.Errorf("invalid %s, expected RFC3339 time: %s", key, s)
+		}
+	}
+
+	filter.TitlePrefix = c.Query("title_prefix")
+	filter.SortBy = domain.ArticleSortField(c.Query("sort"))
+	return filter, nil
+}
+
+// pageLink builds an RFC 8288 link to the page in given direction, relative to the requested URL
+func pageLink(u *url.URL, cursor string, direction domain.Direction, rel string) string {
+	query := u.Query()
+	query.Set("cursor", cursor)
+	if direction != "" {
+		query.Set("direction", string(direction))
+	}
+
+	link := url.URL{Path: u.Path, RawQuery: query.Encode()}
+	return fmt.Sprintf(`<%s>; rel="%s"`, link.String(), rel)
+}
+
+// SearchArticle will search the articles by the keywords in the `q` param, ordered by relevance
+func (a *ArticleHandler) SearchArticle(c *gin.Context) {
+	n := c.Query("num")
+	num, _ := strconv.Atoi(n)
+
+	q := c.Query("q")
+	cursor := c.Query("cursor")
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	listAr, nextCursor, err := a.ArticleUsecase.Search(ctx, q, cursor, int64(num))
+	if err != nil {
+		c.AbortWithStatusJSON(getStatusCode(ctx, err), ResponseError{Message: err.Error()})
+		return
+	}
+
+	if nextCursor != "" {
+		c.Header("Link", pageLink(c.Request.URL, nextCursor, "", "next"))
+	}
 	c.Header("X-Cursor", nextCursor)
 	c.JSON(http.StatusOK, listAr)
 }
@@ -59,22 +159,113 @@ func (a *ArticleHandler) GetByID(c *gin.Context) {
 	}
 
 	id := int64(i)
-	ctx, cancel := context.WithCancel(c)
+	ctx, cancel := context.WithCancel(c.Request.Context())
 	defer cancel()
 
 	ar, err := a.ArticleUsecase.GetByID(ctx, id)
 	if err != nil {
-		c.AbortWithStatusJSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		c.AbortWithStatusJSON(getStatusCode(ctx, err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, ar)
+}
+
+// Store will store the article by given request body
+func (a *ArticleHandler) Store(c *gin.Context) {
+	var ar domain.Article
+	if err := c.ShouldBindJSON(&ar); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if !a.isRequestValid(ctx, c, &ar) {
+		return
+	}
+
+	err := a.ArticleUsecase.Store(ctx, &ar)
+	if err != nil {
+		c.AbortWithStatusJSON(getStatusCode(ctx, err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, ar)
+}
+
+// Update will update the article by given id and request body
+func (a *ArticleHandler) Update(c *gin.Context) {
+	i, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, ResponseError{Message: domain.ErrNotFound.Error()})
+		return
+	}
+
+	var ar domain.Article
+	if err := c.ShouldBindJSON(&ar); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+		return
+	}
+	ar.ID = int64(i)
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if !a.isRequestValid(ctx, c, &ar) {
+		return
+	}
+
+	err = a.ArticleUsecase.Update(ctx, &ar)
+	if err != nil {
+		c.AbortWithStatusJSON(getStatusCode(ctx, err), ResponseError{Message: err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, ar)
 }
 
-func getStatusCode(err error) int {
+// Delete will delete the article by given id
+func (a *ArticleHandler) Delete(c *gin.Context) {
+	i, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, ResponseError{Message: domain.ErrNotFound.Error()})
+		return
+	}
+
+	id := int64(i)
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	err = a.ArticleUsecase.Delete(ctx, id)
+	if err != nil {
+		c.AbortWithStatusJSON(getStatusCode(ctx, err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// isRequestValid aborts the request with the list of invalid fields when given article is not valid
+func (a *ArticleHandler) isRequestValid(ctx context.Context, c *gin.Context, ar *domain.Article) bool {
+	fieldErrors, err := a.Validator.Validate(ctx, ar)
+	if err != nil {
+		c.AbortWithStatusJSON(getStatusCode(ctx, err), ResponseError{Message: err.Error()})
+		return false
+	}
+
+	if len(fieldErrors) > 0 {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, ResponseError{
+			Message: domain.ErrBadParamInput.Error(),
+			Errors:  fieldErrors,
+		})
+		return false
+	}
+	return true
+}
+
+func getStatusCode(ctx context.Context, err error) int {
 	if err == nil {
 		return http.StatusOK
 	}
-	logrus.Error(err)
+	logging.FromContext(ctx).Error(err)
 	switch err {
 	case domain.ErrInternalServer:
 		return http.StatusInternalServerError
@@ -82,6 +273,10 @@ func getStatusCode(err error) int {
 		return http.StatusNotFound
 	case domain.ErrAlreadyExist:
 		return http.StatusConflict
+	case domain.ErrBadParamInput:
+		return http.StatusBadRequest
+	case domain.ErrForbidden:
+		return http.StatusForbidden
 	default:
 		return http.StatusInternalServerError
 	}
@@ -0,0 +1,334 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	articleHttp "github.com/phantomnat/go-clean-architecture/article/delivery/http"
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/domain/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func newMockAuthorUsecase() *mocks.AuthorUsecase {
+	mockAuthorUCase := new(mocks.AuthorUsecase)
+	mockAuthorUCase.On("GetByID", mock.Anything, int64(1)).Return(domain.Author{ID: 1, Name: "Iron Man"}, nil)
+	mockAuthorUCase.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(domain.Author{}, domain.ErrNotFound)
+	return mockAuthorUCase
+}
+
+func TestFetch(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+		mockListArticle := []domain.Article{{ID: 1, Title: "Title", Content: "Content"}}
+		mockUCase.On("Fetch", mock.Anything, domain.ArticleFilter{}, "abc", int64(1), domain.Direction(""), domain.SortDesc).
+			Return(mockListArticle, "next-cursor", "prev-cursor", nil).Once()
+
+		e := gin.New()
+		articleHttp.NewArticleHttpHandler(e, mockUCase, articleHttp.NewArticleValidator(newMockAuthorUsecase()))
+
+		req := httptest.NewRequest(http.MethodGet, "/articles?num=1&cursor=abc&order=desc", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "next-cursor", rec.Header().Get("X-Cursor"))
+		assert.Equal(t,
+			`</articles?cursor=next-cursor&direction=next&num=1&order=desc>; rel="next", `+
+				`</articles?cursor=prev-cursor&direction=prev&num=1&order=desc>; rel="prev"`,
+			rec.Header().Get("Link"))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("bad param", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+		mockUCase.On("Fetch", mock.Anything, domain.ArticleFilter{}, "", int64(0), domain.Direction("sideways"), domain.SortOrder("")).
+			Return(nil, "", "", domain.ErrBadParamInput).Once()
+
+		e := gin.New()
+		articleHttp.NewArticleHttpHandler(e, mockUCase, articleHttp.NewArticleValidator(newMockAuthorUsecase()))
+
+		req := httptest.NewRequest(http.MethodGet, "/articles?direction=sideways", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Empty(t, rec.Header().Get("Link"))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("with filter", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+		filter := domain.ArticleFilter{
+			AuthorID:    3,
+			CreatedFrom: time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC),
+			TitlePrefix: "Go",
+			SortBy:      domain.ArticleSortUpdatedAt,
+		}
+		mockUCase.On("Fetch", mock.Anything, filter, "", int64(0), domain.Direction(""), domain.SortOrder("")).
+			Return([]domain.Article{}, "", "", nil).Once()
+
+		e := gin.New()
+		articleHttp.NewArticleHttpHandler(e, mockUCase, articleHttp.NewArticleValidator(newMockAuthorUsecase()))
+
+		req := httptest.NewRequest(http.MethodGet,
+			"/articles?author_id=3&created_from=2019-05-01T00:00:00Z&title_prefix=Go&sort=updated_at", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+
+		e := gin.New()
+		articleHttp.NewArticleHttpHandler(e, mockUCase, articleHttp.NewArticleValidator(newMockAuthorUsecase()))
+
+		req := httptest.NewRequest(http.MethodGet, "/articles?updated_to=yesterday", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestSearch(t *testing.T) {
+	mockUCase := new(mocks.ArticleUsecase)
+	mockResult := []domain.ArticleSearchResult{{Article: domain.Article{ID: 1, Title: "Title"}, Score: 1}}
+	mockUCase.On("Search", mock.Anything, "clean go", "", int64(1)).Return(mockResult, "next-cursor", nil).Once()
+
+	e := gin.New()
+	articleHttp.NewArticleHttpHandler(e, mockUCase, articleHttp.NewArticleValidator(newMockAuthorUsecase()))
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/search?q=clean+go&num=1", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `</articles/search?cursor=next-cursor&num=1&q=clean+go>; rel="next"`, rec.Header().Get("Link"))
+	mockUCase.AssertExpectations(t)
+}
+
+func TestStore(t *testing.T) {
+	mockArticle := domain.Article{
+		Title:   "Title",
+		Content: "Content",
+		Author:  domain.Author{ID: 1},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+		mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+
+		e := gin.New()
+		articleHttp.NewArticleHttpHandler(e, mockUCase, articleHttp.NewArticleValidator(newMockAuthorUsecase()))
+
+		body, err := json.Marshal(mockArticle)
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/articles", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("already exist", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+		mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).
+			Return(domain.ErrAlreadyExist).Once()
+
+		e := gin.New()
+		articleHttp.NewArticleHttpHandler(e, mockUCase, articleHttp.NewArticleValidator(newMockAuthorUsecase()))
+
+		body, err := json.Marshal(mockArticle)
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/articles", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+
+		e := gin.New()
+		articleHttp.NewArticleHttpHandler(e, mockUCase, articleHttp.NewArticleValidator(newMockAuthorUsecase()))
+
+		req := httptest.NewRequest(http.MethodPost, "/articles", bytes.NewReader([]byte("{")))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("validation failed", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+
+		e := gin.New()
+		articleHttp.NewArticleHttpHandler(e, mockUCase, articleHttp.NewArticleValidator(newMockAuthorUsecase()))
+
+		body, err := json.Marshal(domain.Article{
+			// 21846 characters but 65538 bytes, more than the TEXT column holds
+			Content: strings.Repeat("ก", 21846),
+			Author:  domain.Author{ID: 2},
+		})
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/articles", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+		var resp articleHttp.ResponseError
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+
+		fields := make([]string, 0)
+		for _, fe := range resp.Errors {
+			fields = append(fields, fe.Field+":"+fe.Tag)
+		}
+		assert.Equal(t, []string{"title:required", "content:maxbytes", "author.id:exists"}, fields)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	mockArticle := domain.Article{
+		Title:   "Title",
+		Content: "Content",
+		Author:  domain.Author{ID: 1},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+		mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool {
+			return ar.ID == 12
+		})).Return(nil).Once()
+
+		e := gin.New()
+		articleHttp.NewArticleHttpHandler(e, mockUCase, articleHttp.NewArticleValidator(newMockAuthorUsecase()))
+
+		body, err := json.Marshal(mockArticle)
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPut, "/articles/12", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+		mockUCase.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).
+			Return(domain.ErrNotFound).Once()
+
+		e := gin.New()
+		articleHttp.NewArticleHttpHandler(e, mockUCase, articleHttp.NewArticleValidator(newMockAuthorUsecase()))
+
+		body, err := json.Marshal(mockArticle)
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPut, "/articles/12", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+		mockUCase.On("Delete", mock.Anything, int64(12)).Return(nil).Once()
+
+		e := gin.New()
+		articleHttp.NewArticleHttpHandler(e, mockUCase, articleHttp.NewArticleValidator(newMockAuthorUsecase()))
+
+		req := httptest.NewRequest(http.MethodDelete, "/articles/12", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("bad param", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+		mockUCase.On("Delete", mock.Anything, int64(12)).Return(domain.ErrBadParamInput).Once()
+
+		e := gin.New()
+		articleHttp.NewArticleHttpHandler(e, mockUCase, articleHttp.NewArticleValidator(newMockAuthorUsecase()))
+
+		req := httptest.NewRequest(http.MethodDelete, "/articles/12", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+		mockUCase.On("Delete", mock.Anything, int64(12)).Return(domain.ErrForbidden).Once()
+
+		e := gin.New()
+		articleHttp.NewArticleHttpHandler(e, mockUCase, articleHttp.NewArticleValidator(newMockAuthorUsecase()))
+
+		req := httptest.NewRequest(http.MethodDelete, "/articles/12", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.JSONEq(t, `{"message":"you are not allowed to perform this action"}`, rec.Body.String())
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestScope(t *testing.T) {
+	mockUCase := new(mocks.ArticleUsecase)
+	mockUCase.On("GetByID", mock.Anything, int64(12)).Return(domain.Article{ID: 12}, nil).Once()
+
+	e := gin.New()
+	e.Use(func(c *gin.Context) {
+		p := domain.Principal{Subject: "apikey:gca_1234", APIKeyID: 1, Scopes: []domain.APIKeyScope{domain.ScopeArticlesRead}}
+		c.Request = c.Request.WithContext(domain.ContextWithPrincipal(c.Request.Context(), p))
+	})
+	articleHttp.NewArticleHttpHandler(e, mockUCase, articleHttp.NewArticleValidator(newMockAuthorUsecase()))
+
+	req := httptest.NewRequest(http.MethodGet, "/article/12", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/articles/12", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// FieldError represents a single invalid field in the request body
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Message string `json:"message"`
+}
+
+// ArticleValidator validates the article request body before it is passed to the usecase
+type ArticleValidator struct {
+	validate      *validator.Validate
+	authorUsecase domain.AuthorUsecase
+}
+
+// NewArticleValidator will create a validator that checks the `validate` tags of domain.Article
+// and the existence of the article's author
+func NewArticleValidator(au domain.AuthorUsecase) *ArticleValidator {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	// the TEXT columns hold 65535 bytes, which is fewer characters with the multibyte ones
+	v.RegisterValidation("maxbytes", func(fl validator.FieldLevel) bool {
+		max, err := strconv.Atoi(fl.Param())
+		return err == nil && len(fl.Field().String()) <= max
+	})
+
+	return &ArticleValidator{
+		validate:      v,
+		authorUsecase: au,
+	}
+}
+
+// Validate returns the list of invalid fields of given article.
+// The error is only returned when the author could not be looked up.
+func (v *ArticleValidator) Validate(ctx context.Context, ar *domain.Article) ([]FieldError, error) {
+	fieldErrors := make([]FieldError, 0)
+
+	err := v.validate.StructCtx(ctx, ar)
+	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+		for _, fe := range validationErrors {
+			fieldErrors = append(fieldErrors, newFieldError(fe))
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if ar.Author.ID == 0 {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   "author.id",
+			Tag:     "required",
+			Message: "author.id is required",
+		})
+		return fieldErrors, nil
+	}
+
+	_, err = v.authorUsecase.GetByID(ctx, ar.Author.ID)
+	switch err {
+	case nil:
+	case domain.ErrNotFound:
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   "author.id",
+			Tag:     "exists",
+			Message: fmt.Sprintf("author %d does not exist", ar.Author.ID),
+		})
+	default:
+		return nil, err
+	}
+
+	return fieldErrors, nil
+}
+
+func newFieldError(fe validator.FieldError) FieldError {
+	// strip the root struct name from the namespace, e.g. Article.title => title
+	field := fe.Namespace()
+	if i := strings.Index(field, "."); i >= 0 {
+		field = field[i+1:]
+	}
+
+	var msg string
+	switch fe.Tag() {
+	case "required":
+		msg = fmt.Sprintf("%s is required", field)
+	case "max":
+		msg = fmt.Sprintf("%s must be at most %s characters long", field, fe.Param())
+	case "maxbytes":
+		msg = fmt.Sprintf("%s must be at most %s bytes long", field, fe.Param())
+	case "min":
+		msg = fmt.Sprintf("%s must be at least %s characters long", field, fe.Param())
+	default:
+		msg = fmt.Sprintf("%s failed on the '%s' rule", field, fe.Tag())
+	}
+
+	return FieldError{
+		Field:   field,
+		Tag:     fe.Tag(),
+		Message: msg,
+	}
+}
@@ -2,29 +2,99 @@ package repository
 
 import (
 	"encoding/base64"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
 )
 
 const (
 	timeFormat = "2006-01-02T15:04:05.999Z07:00" // reduce precision from RFC3339Nano as date format
+
+	cursorVersion   = "v2"
+	cursorSeparator = "|"
 )
 
-// DecodeCursor will decode cursor from user for mysql
-func DecodeCursor(encodedTime string) (time.Time, error) {
-	byt, err := base64.StdEncoding.DecodeString(encodedTime)
+// ErrInvalidCursor is returned when the given cursor could not be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor represents the position of an article in a list ordered by (SortBy, id)
+type Cursor struct {
+	SortBy domain.ArticleSortField
+	Value  time.Time
+	ID     int64
+}
+
+// DecodeCursor will decode cursor from user for mysql.
+// Legacy timestamp-only cursors are still accepted, they are positioned after every article
+// created at the same time so the pagination continues like before.
+func DecodeCursor(encodedCursor string) (Cursor, error) {
+	byt, err := base64.StdEncoding.DecodeString(encodedCursor)
 	if err != nil {
-		return time.Time{}, err
+		return Cursor{}, err
 	}
 
-	timeString := string(byt)
-	t, err := time.Parse(timeFormat, timeString)
+	parts := strings.Split(string(byt), cursorSeparator)
+	switch {
+	case len(parts) == 1:
+		t, err := time.Parse(timeFormat, parts[0])
+		if err != nil {
+			return Cursor{}, err
+		}
+		return Cursor{SortBy: domain.ArticleSortCreatedAt, Value: t, ID: math.MaxInt64}, nil
+	case parts[0] == "v1" && len(parts) == 3:
+		return parseCursor(domain.ArticleSortCreatedAt, parts[1], parts[2])
+	case parts[0] == cursorVersion && len(parts) == 4:
+		sortBy := domain.ArticleSortField(parts[1])
+		if !sortBy.IsValid() {
+			return Cursor{}, ErrInvalidCursor
+		}
+		return parseCursor(sortBy, parts[2], parts[3])
+	default:
+		return Cursor{}, ErrInvalidCursor
+	}
+}
+
+func parseCursor(sortBy domain.ArticleSortField, value, id string) (Cursor, error) {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return Cursor{}, err
+	}
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return Cursor{}, err
+	}
 
-	return t, err
+	return Cursor{SortBy: sortBy, Value: t, ID: i}, nil
 }
 
 // EncodeCursor will encode cursor from mysql to user
-func EncodeCursor(t time.Time) string {
-	timeString := t.Format(timeFormat)
+func EncodeCursor(c Cursor) string {
+	cursorString := strings.Join([]string{
+		cursorVersion,
+		string(c.SortBy),
+		c.Value.Format(time.RFC3339Nano),
+		strconv.FormatInt(c.ID, 10),
+	}, cursorSeparator)
+
+	return base64.StdEncoding.EncodeToString([]byte(cursorString))
+}
+
+// NewCursor will create the cursor positioned at given article
+func NewCursor(ar domain.Article, sortBy domain.ArticleSortField) Cursor {
+	value := ar.CreatedAt
+	if sortBy == domain.ArticleSortUpdatedAt {
+		value = ar.UpdatedAt
+	}
+
+	return Cursor{SortBy: sortBy, Value: value, ID: ar.ID}
+}
 
-	return base64.StdEncoding.EncodeToString([]byte(timeString))
+// EscapeLike will escape the wildcard characters of a LIKE pattern with a backslash
+func EscapeLike(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(s)
 }
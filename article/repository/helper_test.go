@@ -0,0 +1,64 @@
+package repository_test
+
+import (
+	"encoding/base64"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/article/repository"
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		c := repository.Cursor{
+			SortBy: domain.ArticleSortUpdatedAt,
+			Value:  time.Date(2019, 5, 10, 8, 30, 0, 123456789, time.UTC),
+			ID:     42,
+		}
+
+		decoded, err := repository.DecodeCursor(repository.EncodeCursor(c))
+		assert.NoError(t, err)
+		assert.Equal(t, c.SortBy, decoded.SortBy)
+		assert.Equal(t, c.ID, decoded.ID)
+		assert.True(t, c.Value.Equal(decoded.Value))
+	})
+
+	t.Run("v1 cursor", func(t *testing.T) {
+		v1 := base64.StdEncoding.EncodeToString([]byte("v1|2019-05-10T08:30:00.123456789Z|42"))
+
+		decoded, err := repository.DecodeCursor(v1)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ArticleSortCreatedAt, decoded.SortBy)
+		assert.Equal(t, int64(42), decoded.ID)
+		assert.True(t, time.Date(2019, 5, 10, 8, 30, 0, 123456789, time.UTC).Equal(decoded.Value))
+	})
+
+	t.Run("legacy timestamp cursor", func(t *testing.T) {
+		legacy := base64.StdEncoding.EncodeToString([]byte("2019-05-10T08:30:00.123Z"))
+
+		decoded, err := repository.DecodeCursor(legacy)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ArticleSortCreatedAt, decoded.SortBy)
+		assert.Equal(t, int64(math.MaxInt64), decoded.ID)
+		assert.True(t, time.Date(2019, 5, 10, 8, 30, 0, 123000000, time.UTC).Equal(decoded.Value))
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		_, err := repository.DecodeCursor(base64.StdEncoding.EncodeToString([]byte("v1|not-a-time")))
+		assert.Error(t, err)
+
+		_, err = repository.DecodeCursor(base64.StdEncoding.EncodeToString([]byte("v2|title|2019-05-10T08:30:00Z|1")))
+		assert.Error(t, err)
+
+		_, err = repository.DecodeCursor("%%%")
+		assert.Error(t, err)
+	})
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `100\% \_real\\`, repository.EscapeLike(`100% _real\`))
+}
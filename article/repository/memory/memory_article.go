@@ -0,0 +1,283 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/article/repository"
+	"github.com/phantomnat/go-clean-architecture/domain"
+)
+
+type memoryArticleRepository struct {
+	mu       sync.RWMutex
+	articles map[int64]domain.Article
+	lastID   int64
+}
+
+// NewMemoryArticleRepository will create an object that represent the article.Repository interface.
+// The articles are kept in memory and are lost when the process exits.
+func NewMemoryArticleRepository() domain.ArticleRepository {
+	return &memoryArticleRepository{
+		articles: make(map[int64]domain.Article),
+	}
+}
+
+// sortValue returns the value of the column the list is sorted by
+func sortValue(ar domain.Article, sortBy domain.ArticleSortField) time.Time {
+	if sortBy == domain.ArticleSortUpdatedAt {
+		return ar.UpdatedAt
+	}
+	return ar.CreatedAt
+}
+
+// less reports whether a is before b in the list ordered by (sortBy, id)
+func less(a, b domain.Article, sortBy domain.ArticleSortField) bool {
+	va, vb := sortValue(a, sortBy), sortValue(b, sortBy)
+	if !va.Equal(vb) {
+		return va.Before(vb)
+	}
+	return a.ID < b.ID
+}
+
+// compareCursor returns -1, 0 or 1 when the article is before, at or after the cursor
+func compareCursor(ar domain.Article, c repository.Cursor) int {
+	v := sortValue(ar, c.SortBy)
+	switch {
+	case v.Before(c.Value):
+		return -1
+	case v.After(c.Value):
+		return 1
+	case ar.ID < c.ID:
+		return -1
+	case ar.ID > c.ID:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func matchFilter(ar domain.Article, filter domain.ArticleFilter) bool {
+	if filter.AuthorID != 0 && ar.Author.ID != filter.AuthorID {
+		return false
+	}
+	if !filter.CreatedFrom.IsZero() && ar.CreatedAt.Before(filter.CreatedFrom) {
+		return false
+	}
+	if !filter.CreatedTo.IsZero() && !ar.CreatedAt.Before(filter.CreatedTo) {
+		return false
+	}
+	if !filter.UpdatedFrom.IsZero() && ar.UpdatedAt.Before(filter.UpdatedFrom) {
+		return false
+	}
+	if !filter.UpdatedTo.IsZero() && !ar.UpdatedAt.Before(filter.UpdatedTo) {
+		return false
+	}
+	// like the case-insensitive collation of the sql backends
+	if filter.TitlePrefix != "" && !strings.HasPrefix(strings.ToLower(ar.Title), strings.ToLower(filter.TitlePrefix)) {
+		return false
+	}
+	return true
+}
+
+func (m *memoryArticleRepository) Fetch(ctx context.Context, filter domain.ArticleFilter, cursor string, num int64,
+	direction domain.Direction, order domain.SortOrder) (res []domain.Article, nextCursor string, prevCursor string, err error) {
+	if num <= 0 {
+		return make([]domain.Article, 0), "", "", nil
+	}
+
+	sortBy := filter.SortBy
+	if sortBy == "" {
+		sortBy = domain.ArticleSortCreatedAt
+	}
+	if !sortBy.IsValid() {
+		return nil, "", "", domain.ErrBadParamInput
+	}
+
+	// the previous page is fetched by walking the list backward from the cursor
+	backward := direction == domain.DirectionPrev
+	ascending := (order != domain.SortDesc) != backward
+
+	var decodedCursor repository.Cursor
+	if cursor != "" {
+		decodedCursor, err = repository.DecodeCursor(cursor)
+		if err != nil || decodedCursor.SortBy != sortBy {
+			return nil, "", "", domain.ErrBadParamInput
+		}
+	}
+
+	m.mu.RLock()
+	list := make([]domain.Article, 0)
+	for _, ar := range m.articles {
+		if !matchFilter(ar, filter) {
+			continue
+		}
+		if cursor != "" {
+			cmp := compareCursor(ar, decodedCursor)
+			if (ascending && cmp <= 0) || (!ascending && cmp >= 0) {
+				continue
+			}
+		}
+		list = append(list, ar)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if ascending {
+			return less(list[i], list[j], sortBy)
+		}
+		return less(list[j], list[i], sortBy)
+	})
+	if int64(len(list)) > num {
+		list = list[:num]
+	}
+	res = list
+
+	if backward {
+		reverse(res)
+	}
+
+	if len(res) == 0 {
+		return
+	}
+
+	first, last := res[0], res[len(res)-1]
+	hasMore := len(res) == int(num)
+	if (hasMore && !backward) || (backward && cursor != "") {
+		nextCursor = repository.EncodeCursor(repository.NewCursor(last, sortBy))
+	}
+	if (hasMore && backward) || (!backward && cursor != "") {
+		prevCursor = repository.EncodeCursor(repository.NewCursor(first, sortBy))
+	}
+
+	return
+}
+
+func reverse(list []domain.Article) {
+	for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+		list[i], list[j] = list[j], list[i]
+	}
+}
+
+func (m *memoryArticleRepository) GetByID(ctx context.Context, id int64) (domain.Article, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ar, ok := m.articles[id]
+	if !ok {
+		return domain.Article{}, domain.ErrNotFound
+	}
+	return ar, nil
+}
+
+func (m *memoryArticleRepository) GetByTitle(ctx context.Context, title string) (domain.Article, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.getByTitle(title)
+}
+
+func (m *memoryArticleRepository) getByTitle(title string) (domain.Article, error) {
+	for _, ar := range m.articles {
+		if ar.Title == title {
+			return ar, nil
+		}
+	}
+	return domain.Article{}, domain.ErrNotFound
+}
+
+func (m *memoryArticleRepository) Search(ctx context.Context, q string, cursor string, num int64) (res []domain.ArticleSearchResult, nextCursor string, err error) {
+	if num <= 0 {
+		return make([]domain.ArticleSearchResult, 0), "", nil
+	}
+
+	var decodedCursor repository.SearchCursor
+	if cursor != "" {
+		decodedCursor, err = repository.DecodeSearchCursor(cursor)
+		if err != nil {
+			return nil, "", domain.ErrBadParamInput
+		}
+	}
+
+	terms := repository.SearchTerms(q)
+
+	m.mu.RLock()
+	res = make([]domain.ArticleSearchResult, 0)
+	for _, ar := range m.articles {
+		score := repository.SearchScore(ar, terms)
+		if score == 0 {
+			continue
+		}
+		if cursor != "" && (score > decodedCursor.Score || (score == decodedCursor.Score && ar.ID >= decodedCursor.ID)) {
+			continue
+		}
+		res = append(res, domain.ArticleSearchResult{
+			Article: ar,
+			Score:   score,
+			Snippet: repository.Snippet(ar.Content, terms),
+		})
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		return res[i].ID > res[j].ID
+	})
+
+	if len(res) > int(num) {
+		res = res[:num]
+	}
+	if len(res) == int(num) {
+		last := res[len(res)-1]
+		nextCursor = repository.EncodeSearchCursor(repository.SearchCursor{Score: last.Score, ID: last.ID})
+	}
+
+	return
+}
+
+func (m *memoryArticleRepository) Store(ctx context.Context, a *domain.Article) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastID++
+	a.ID = m.lastID
+	m.articles[a.ID] = stored(*a)
+	return nil
+}
+
+func (m *memoryArticleRepository) Delete(ctx context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.articles[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(m.articles, id)
+	return nil
+}
+
+func (m *memoryArticleRepository) Update(ctx context.Context, ar *domain.Article) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existed, ok := m.articles[ar.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+
+	updated := stored(*ar)
+	// like the sql backends, the creation time is never updated
+	updated.CreatedAt = existed.CreatedAt
+	m.articles[ar.ID] = updated
+	return nil
+}
+
+// stored returns the article as it is persisted, only the id of the author is kept
+func stored(ar domain.Article) domain.Article {
+	ar.Author = domain.Author{ID: ar.Author.ID}
+	return ar
+}
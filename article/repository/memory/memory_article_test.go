@@ -0,0 +1,131 @@
+package memory_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/article/repository/memory"
+	"github.com/phantomnat/go-clean-architecture/article/repository/repositorytest"
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ids(list []domain.Article) []int64 {
+	res := make([]int64, 0, len(list))
+	for _, ar := range list {
+		res = append(res, ar.ID)
+	}
+	return res
+}
+
+func TestFetch(t *testing.T) {
+	a := memory.NewMemoryArticleRepository()
+
+	now := time.Now()
+	for i, c := range []time.Time{now, now.Add(time.Second), now.Add(time.Second), now.Add(2 * time.Second)} {
+		ar := domain.Article{Title: string(rune('a' + i)), Author: domain.Author{ID: int64(i % 2)}, CreatedAt: c, UpdatedAt: c}
+		require.NoError(t, a.Store(context.TODO(), &ar))
+	}
+
+	list, nextCursor, prevCursor, err := a.Fetch(context.TODO(), domain.ArticleFilter{}, "", 2, domain.DirectionNext, domain.SortAsc)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, ids(list))
+	assert.Empty(t, prevCursor)
+
+	list, _, prevCursor, err = a.Fetch(context.TODO(), domain.ArticleFilter{}, nextCursor, 2, domain.DirectionNext, domain.SortAsc)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 4}, ids(list))
+
+	list, _, _, err = a.Fetch(context.TODO(), domain.ArticleFilter{}, prevCursor, 2, domain.DirectionPrev, domain.SortAsc)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, ids(list))
+
+	list, _, _, err = a.Fetch(context.TODO(), domain.ArticleFilter{AuthorID: 1}, "", 10, domain.DirectionNext, domain.SortDesc)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{4, 2}, ids(list))
+
+	_, _, _, err = a.Fetch(context.TODO(), domain.ArticleFilter{SortBy: domain.ArticleSortUpdatedAt}, nextCursor, 2,
+		domain.DirectionNext, domain.SortAsc)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestStore(t *testing.T) {
+	a := memory.NewMemoryArticleRepository()
+
+	ar := domain.Article{Title: "hello", Author: domain.Author{ID: 1, Name: "Iron Man"}}
+	require.NoError(t, a.Store(context.TODO(), &ar))
+	assert.Equal(t, int64(1), ar.ID)
+
+	res, err := a.GetByID(context.TODO(), ar.ID)
+	require.NoError(t, err)
+	// only the id of the author is persisted
+	assert.Equal(t, domain.Author{ID: 1}, res.Author)
+}
+
+func TestUpdateAndDelete(t *testing.T) {
+	a := memory.NewMemoryArticleRepository()
+
+	createdAt := time.Now().Add(-time.Hour)
+	ar := domain.Article{Title: "hello", CreatedAt: createdAt}
+	require.NoError(t, a.Store(context.TODO(), &ar))
+
+	ar.Title = "world"
+	ar.CreatedAt = time.Now()
+	require.NoError(t, a.Update(context.TODO(), &ar))
+	res, err := a.GetByTitle(context.TODO(), "world")
+	require.NoError(t, err)
+	assert.Equal(t, createdAt, res.CreatedAt)
+
+	missing := domain.Article{ID: 99, Title: "missing"}
+	assert.Equal(t, domain.ErrNotFound, a.Update(context.TODO(), &missing))
+
+	require.NoError(t, a.Delete(context.TODO(), ar.ID))
+	assert.Equal(t, domain.ErrNotFound, a.Delete(context.TODO(), ar.ID))
+	_, err = a.GetByID(context.TODO(), ar.ID)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestConcurrentStore(t *testing.T) {
+	a := memory.NewMemoryArticleRepository()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ar := domain.Article{Title: string(rune('A' + i)), CreatedAt: time.Now()}
+			assert.NoError(t, a.Store(context.TODO(), &ar))
+		}(i)
+	}
+	wg.Wait()
+
+	list, _, _, err := a.Fetch(context.TODO(), domain.ArticleFilter{}, "", 100, domain.DirectionNext, domain.SortAsc)
+	require.NoError(t, err)
+	assert.Len(t, list, 50)
+}
+
+func TestSearch(t *testing.T) {
+	a := memory.NewMemoryArticleRepository()
+	for _, ar := range []domain.Article{
+		{Title: "Clean architecture", Content: "Trying clean architecture in Go"},
+		{Title: "Cooking", Content: "Nothing to see"},
+	} {
+		ar := ar
+		require.NoError(t, a.Store(context.TODO(), &ar))
+	}
+
+	list, _, err := a.Search(context.TODO(), "clean", "", 10)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, 3.0, list[0].Score)
+}
+
+func TestContract(t *testing.T) {
+	repositorytest.RunArticleRepositoryTests(t, func(t *testing.T) domain.ArticleRepository {
+		return memory.NewMemoryArticleRepository()
+	})
+}
@@ -4,10 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/phantomnat/go-clean-architecture/article/repository"
 	"github.com/phantomnat/go-clean-architecture/domain"
-	"github.com/sirupsen/logrus"
+	"github.com/phantomnat/go-clean-architecture/logging"
 )
 
 type mysqlArticleRepository struct {
@@ -22,14 +23,14 @@ func NewMysqlArticleRepository(Conn *sql.DB) domain.ArticleRepository {
 func (m *mysqlArticleRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Article, err error) {
 	rows, err := m.Conn.QueryContext(ctx, query, args...)
 	if err != nil {
-		logrus.Error(err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 
 	defer func() {
 		err := rows.Close()
 		if err != nil {
-			logrus.Error(err)
+			logging.FromContext(ctx).Error(err)
 		}
 	}()
 
@@ -47,7 +48,7 @@ func (m *mysqlArticleRepository) fetch(ctx context.Context, query string, args .
 		)
 
 		if err != nil {
-			logrus.Error(err)
+			logging.FromContext(ctx).Error(err)
 			return nil, err
 		}
 		t.Author = domain.Author{
@@ -59,26 +60,117 @@ func (m *mysqlArticleRepository) fetch(ctx context.Context, query string, args .
 	return result, nil
 }
 
-func (m *mysqlArticleRepository) Fetch(ctx context.Context, cursor string, num int64) (res []domain.Article, nextCursor string, err error) {
-	query := `SELECT id,title,content, author_id, updated_at, created_at
-  						FROM article WHERE created_at > ? ORDER BY created_at LIMIT ? `
+func (m *mysqlArticleRepository) Fetch(ctx context.Context, filter domain.ArticleFilter, cursor string, num int64,
+	direction domain.Direction, order domain.SortOrder) (res []domain.Article, nextCursor string, prevCursor string, err error) {
+	// a negative LIMIT is a syntax error to MySQL
+	if num <= 0 {
+		return make([]domain.Article, 0), "", "", nil
+	}
 
-	decodedCursor, err := repository.DecodeCursor(cursor)
-	if err != nil && cursor != "" {
-		return nil, "", domain.ErrBadParamInput
+	sortBy := filter.SortBy
+	if sortBy == "" {
+		sortBy = domain.ArticleSortCreatedAt
+	}
+	// the column name is interpolated into the query, only the known columns are allowed
+	if !sortBy.IsValid() {
+		return nil, "", "", domain.ErrBadParamInput
 	}
 
-	res, err = m.fetch(ctx, query, decodedCursor, num)
+	// the previous page is fetched by walking the list backward from the cursor
+	backward := direction == domain.DirectionPrev
+	ascending := (order != domain.SortDesc) != backward
+
+	conditions, args := filterConditions(filter)
+
+	if cursor != "" {
+		decodedCursor, err := repository.DecodeCursor(cursor)
+		if err != nil || decodedCursor.SortBy != sortBy {
+			return nil, "", "", domain.ErrBadParamInput
+		}
+
+		if ascending {
+			conditions = append(conditions, fmt.Sprintf("(%s, id) > (?, ?)", sortBy))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("(%s, id) < (?, ?)", sortBy))
+		}
+		args = append(args, decodedCursor.Value, decodedCursor.ID)
+	}
+
+	query := `SELECT id,title,content, author_id, updated_at, created_at FROM article`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	if ascending {
+		query += fmt.Sprintf(` ORDER BY %s, id LIMIT ?`, sortBy)
+	} else {
+		query += fmt.Sprintf(` ORDER BY %s DESC, id DESC LIMIT ?`, sortBy)
+	}
+	args = append(args, num)
+
+	res, err = m.fetch(ctx, query, args...)
 	if err != nil {
-		return nil, "", err
+		return nil, "", "", err
 	}
 
-	if len(res) == int(num) {
-		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+	if backward {
+		reverse(res)
+	}
+
+	if len(res) == 0 {
+		return
+	}
+
+	first, last := res[0], res[len(res)-1]
+	hasMore := len(res) == int(num)
+	if (hasMore && !backward) || (backward && cursor != "") {
+		nextCursor = repository.EncodeCursor(repository.NewCursor(last, sortBy))
+	}
+	if (hasMore && backward) || (!backward && cursor != "") {
+		prevCursor = repository.EncodeCursor(repository.NewCursor(first, sortBy))
 	}
 
 	return
 }
+
+// filterConditions builds the parameterised WHERE conditions of given filter
+func filterConditions(filter domain.ArticleFilter) (conditions []string, args []interface{}) {
+	conditions = make([]string, 0)
+	args = make([]interface{}, 0)
+
+	if filter.AuthorID != 0 {
+		conditions = append(conditions, "author_id = ?")
+		args = append(args, filter.AuthorID)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.CreatedTo)
+	}
+	if !filter.UpdatedFrom.IsZero() {
+		conditions = append(conditions, "updated_at >= ?")
+		args = append(args, filter.UpdatedFrom)
+	}
+	if !filter.UpdatedTo.IsZero() {
+		conditions = append(conditions, "updated_at < ?")
+		args = append(args, filter.UpdatedTo)
+	}
+	if filter.TitlePrefix != "" {
+		conditions = append(conditions, "title LIKE ?")
+		args = append(args, repository.EscapeLike(filter.TitlePrefix)+"%")
+	}
+
+	return
+}
+
+func reverse(list []domain.Article) {
+	for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+		list[i], list[j] = list[j], list[i]
+	}
+}
+
 func (m *mysqlArticleRepository) GetByID(ctx context.Context, id int64) (res domain.Article, err error) {
 	query := `SELECT id,title,content, author_id, updated_at, created_at
   						FROM article WHERE ID = ?`
@@ -114,6 +206,75 @@ func (m *mysqlArticleRepository) GetByTitle(ctx context.Context, title string) (
 	return
 }
 
+// Search requires the FULLTEXT index on (title, content) of the article table
+func (m *mysqlArticleRepository) Search(ctx context.Context, q string, cursor string, num int64) (res []domain.ArticleSearchResult, nextCursor string, err error) {
+	if num <= 0 {
+		return make([]domain.ArticleSearchResult, 0), "", nil
+	}
+
+	match := `MATCH(title, content) AGAINST (? IN NATURAL LANGUAGE MODE)`
+	query := `SELECT id,title,content, author_id, updated_at, created_at, ` + match + ` AS score
+  						FROM article WHERE ` + match
+	args := []interface{}{q, q}
+
+	if cursor != "" {
+		decodedCursor, err := repository.DecodeSearchCursor(cursor)
+		if err != nil {
+			return nil, "", domain.ErrBadParamInput
+		}
+		query += ` AND (` + match + `, id) < (?, ?)`
+		args = append(args, q, decodedCursor.Score, decodedCursor.ID)
+	}
+	query += ` ORDER BY score DESC, id DESC LIMIT ?`
+	args = append(args, num)
+
+	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, "", err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	terms := repository.SearchTerms(q)
+	res = make([]domain.ArticleSearchResult, 0)
+	for rows.Next() {
+		t := domain.ArticleSearchResult{}
+		authorID := int64(0)
+		err = rows.Scan(
+			&t.ID,
+			&t.Title,
+			&t.Content,
+			&authorID,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+			&t.Score,
+		)
+
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, "", err
+		}
+		t.Author = domain.Author{
+			ID: authorID,
+		}
+		t.Snippet = repository.Snippet(t.Content, terms)
+		res = append(res, t)
+	}
+
+	if len(res) == int(num) {
+		last := res[len(res)-1]
+		nextCursor = repository.EncodeSearchCursor(repository.SearchCursor{Score: last.Score, ID: last.ID})
+	}
+
+	return
+}
+
 func (m *mysqlArticleRepository) Store(ctx context.Context, a *domain.Article) (err error) {
 	query := `INSERT  article SET title=? , content=? , author_id=?, updated_at=? , created_at=?`
 	stmt, err := m.Conn.PrepareContext(ctx, query)
@@ -151,6 +312,10 @@ func (m *mysqlArticleRepository) Delete(ctx context.Context, id int64) (err erro
 		return
 	}
 
+	if rowsAfected == 0 {
+		err = domain.ErrNotFound
+		return
+	}
 	if rowsAfected != 1 {
 		err = fmt.Errorf("Weird  Behaviour. Total Affected: %d", rowsAfected)
 		return
@@ -174,6 +339,10 @@ func (m *mysqlArticleRepository) Update(ctx context.Context, ar *domain.Article)
 	if err != nil {
 		return
 	}
+	if affect == 0 {
+		err = domain.ErrNotFound
+		return
+	}
 	if affect != 1 {
 		err = fmt.Errorf("Weird  Behaviour. Total Affected: %d", affect)
 		return
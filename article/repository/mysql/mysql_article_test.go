@@ -2,14 +2,19 @@ package mysql_test
 
 import (
 	"context"
+	"database/sql"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/go-sql-driver/mysql"
 	"github.com/phantomnat/go-clean-architecture/article/repository"
 	"github.com/phantomnat/go-clean-architecture/article/repository/mysql"
+	"github.com/phantomnat/go-clean-architecture/article/repository/repositorytest"
 	"github.com/phantomnat/go-clean-architecture/domain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFetch(t *testing.T) {
@@ -41,16 +46,154 @@ func TestFetch(t *testing.T) {
 		AddRow(mockArticles[1].ID, mockArticles[1].Title, mockArticles[1].Content,
 			mockArticles[1].Author.ID, mockArticles[1].UpdatedAt, mockArticles[1].CreatedAt)
 
-	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article WHERE created_at > \\? ORDER BY created_at LIMIT \\?"
+	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article WHERE \\(created_at, id\\) > \\(\\?, \\?\\) ORDER BY created_at, id LIMIT \\?"
 
-	mock.ExpectQuery(query).WillReturnRows(rows)
+	mock.ExpectQuery(query).WithArgs(sqlmock.AnyArg(), mockArticles[1].ID, int64(2)).WillReturnRows(rows)
 	a := mysql.NewMysqlArticleRepository(db)
-	cursor := repository.EncodeCursor(mockArticles[1].CreatedAt)
+	cursor := repository.EncodeCursor(repository.NewCursor(mockArticles[1], domain.ArticleSortCreatedAt))
 	num := int64(2)
-	list, nextCursor, err := a.Fetch(context.TODO(), cursor, num)
+	list, nextCursor, prevCursor, err := a.Fetch(context.TODO(), domain.ArticleFilter{}, cursor, num, domain.DirectionNext, domain.SortAsc)
 	assert.NotEmpty(t, nextCursor)
+	assert.NotEmpty(t, prevCursor)
 	assert.NoError(t, err)
 	assert.Len(t, list, 2)
+
+	decoded, err := repository.DecodeCursor(nextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, mockArticles[1].ID, decoded.ID)
+	assert.True(t, mockArticles[1].CreatedAt.Equal(decoded.Value))
+}
+
+func TestFetchPrevDesc(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
+		AddRow(3, "title 3", "content 3", 1, now, now).
+		AddRow(4, "title 4", "content 4", 1, now, now)
+
+	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article WHERE \\(created_at, id\\) > \\(\\?, \\?\\) ORDER BY created_at, id LIMIT \\?"
+
+	mock.ExpectQuery(query).WithArgs(sqlmock.AnyArg(), int64(2), int64(2)).WillReturnRows(rows)
+	a := mysql.NewMysqlArticleRepository(db)
+	cursor := repository.EncodeCursor(repository.Cursor{SortBy: domain.ArticleSortCreatedAt, Value: now, ID: 2})
+	list, nextCursor, prevCursor, err := a.Fetch(context.TODO(), domain.ArticleFilter{}, cursor, int64(2), domain.DirectionPrev, domain.SortDesc)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	// the page is still listed newest first
+	assert.Equal(t, int64(4), list[0].ID)
+	assert.Equal(t, int64(3), list[1].ID)
+	assert.NotEmpty(t, nextCursor)
+	assert.NotEmpty(t, prevCursor)
+
+	decoded, err := repository.DecodeCursor(prevCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), decoded.ID)
+}
+
+func TestFetchFirstPage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
+		AddRow(1, "title 1", "content 1", 1, time.Now(), time.Now())
+
+	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article ORDER BY created_at DESC, id DESC LIMIT \\?"
+
+	mock.ExpectQuery(query).WithArgs(int64(2)).WillReturnRows(rows)
+	a := mysql.NewMysqlArticleRepository(db)
+	list, nextCursor, prevCursor, err := a.Fetch(context.TODO(), domain.ArticleFilter{}, "", int64(2), domain.DirectionNext, domain.SortDesc)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Empty(t, nextCursor)
+	assert.Empty(t, prevCursor)
+}
+
+func TestFetchWithFilter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
+		AddRow(1, "go_lang 1", "content 1", 7, now, now)
+
+	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article " +
+		"WHERE author_id = \\? AND created_at >= \\? AND created_at < \\? AND title LIKE \\? AND \\(updated_at, id\\) < \\(\\?, \\?\\) " +
+		"ORDER BY updated_at DESC, id DESC LIMIT \\?"
+
+	filter := domain.ArticleFilter{
+		AuthorID:    7,
+		CreatedFrom: now.Add(-time.Hour),
+		CreatedTo:   now,
+		TitlePrefix: "go_",
+		SortBy:      domain.ArticleSortUpdatedAt,
+	}
+	mock.ExpectQuery(query).
+		WithArgs(int64(7), sqlmock.AnyArg(), sqlmock.AnyArg(), "go\\_%", sqlmock.AnyArg(), int64(9), int64(1)).
+		WillReturnRows(rows)
+	a := mysql.NewMysqlArticleRepository(db)
+	cursor := repository.EncodeCursor(repository.Cursor{SortBy: domain.ArticleSortUpdatedAt, Value: now, ID: 9})
+	list, nextCursor, _, err := a.Fetch(context.TODO(), filter, cursor, int64(1), domain.DirectionNext, domain.SortDesc)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	decoded, err := repository.DecodeCursor(nextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ArticleSortUpdatedAt, decoded.SortBy)
+}
+
+func TestFetchCursorSortMismatch(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	a := mysql.NewMysqlArticleRepository(db)
+	cursor := repository.EncodeCursor(repository.Cursor{SortBy: domain.ArticleSortCreatedAt, Value: time.Now(), ID: 9})
+	filter := domain.ArticleFilter{SortBy: domain.ArticleSortUpdatedAt}
+	_, _, _, err = a.Fetch(context.TODO(), filter, cursor, int64(1), domain.DirectionNext, domain.SortAsc)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestSearch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "score"}).
+		AddRow(5, "Clean architecture", "Trying clean architecture in Go", 1, time.Now(), time.Now(), 1.5)
+
+	query := "SELECT id,title,content, author_id, updated_at, created_at, " +
+		"MATCH\\(title, content\\) AGAINST \\(\\? IN NATURAL LANGUAGE MODE\\) AS score " +
+		"FROM article WHERE MATCH\\(title, content\\) AGAINST \\(\\? IN NATURAL LANGUAGE MODE\\) " +
+		"AND \\(MATCH\\(title, content\\) AGAINST \\(\\? IN NATURAL LANGUAGE MODE\\), id\\) < \\(\\?, \\?\\) " +
+		"ORDER BY score DESC, id DESC LIMIT \\?"
+
+	mock.ExpectQuery(query).WithArgs("clean", "clean", "clean", 2.5, int64(9), int64(1)).WillReturnRows(rows)
+	a := mysql.NewMysqlArticleRepository(db)
+
+	cursor := repository.EncodeSearchCursor(repository.SearchCursor{Score: 2.5, ID: 9})
+	list, nextCursor, err := a.Search(context.TODO(), "clean", cursor, int64(1))
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, 1.5, list[0].Score)
+	assert.Equal(t, "Trying <mark>clean</mark> architecture in Go", list[0].Snippet)
+	assert.NotEmpty(t, nextCursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestGetByID(t *testing.T) {
@@ -197,3 +340,23 @@ func TestUpdate(t *testing.T) {
 	err = a.Update(context.TODO(), ar)
 	assert.NoError(t, err)
 }
+
+// TestContract runs against a real MySQL server, e.g.
+// MYSQL_TEST_DSN="root:123456@tcp(localhost:3306)/article_test?parseTime=1&clientFoundRows=true"
+// The database must be migrated with `migrate up`, the article table is emptied before every test case.
+func TestContract(t *testing.T) {
+	dsn := os.Getenv("MYSQL_TEST_DSN")
+	if dsn == "" {
+		t.Skip("MYSQL_TEST_DSN is not set")
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	require.NoError(t, err)
+	defer db.Close()
+
+	repositorytest.RunArticleRepositoryTests(t, func(t *testing.T) domain.ArticleRepository {
+		_, err := db.Exec("TRUNCATE TABLE article")
+		require.NoError(t, err)
+		return mysql.NewMysqlArticleRepository(db)
+	})
+}
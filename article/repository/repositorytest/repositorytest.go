@@ -0,0 +1,304 @@
+// Package repositorytest provides the conformance suite of domain.ArticleRepository,
+// every storage backend runs it from its own tests to be verified the same way.
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// maxPages stops a pagination walk that never ends
+const maxPages = 100
+
+// ArticleRepositoryFactory returns an empty repository, it is called once for every test case
+type ArticleRepositoryFactory func(t *testing.T) domain.ArticleRepository
+
+// RunArticleRepositoryTests checks the contract shared by every domain.ArticleRepository implementation
+func RunArticleRepositoryTests(t *testing.T, newRepo ArticleRepositoryFactory) {
+	t.Run("StoreAndGet", func(t *testing.T) { testStoreAndGet(t, newRepo(t)) })
+	t.Run("GetNotFound", func(t *testing.T) { testGetNotFound(t, newRepo(t)) })
+	t.Run("Update", func(t *testing.T) { testUpdate(t, newRepo(t)) })
+	t.Run("DuplicatedTitle", func(t *testing.T) { testDuplicatedTitle(t, newRepo(t)) })
+	t.Run("Delete", func(t *testing.T) { testDelete(t, newRepo(t)) })
+	t.Run("FetchEmpty", func(t *testing.T) { testFetchEmpty(t, newRepo(t)) })
+	t.Run("FetchFilter", func(t *testing.T) { testFetchFilter(t, newRepo(t)) })
+	t.Run("NonPositiveNum", func(t *testing.T) { testNonPositiveNum(t, newRepo(t)) })
+
+	for _, sortBy := range []domain.ArticleSortField{domain.ArticleSortCreatedAt, domain.ArticleSortUpdatedAt} {
+		for _, order := range []domain.SortOrder{domain.SortAsc, domain.SortDesc} {
+			sortBy, order := sortBy, order
+			t.Run(fmt.Sprintf("Paginate/%s/%s", sortBy, order), func(t *testing.T) {
+				testPaginate(t, newRepo(t), sortBy, order)
+			})
+		}
+	}
+}
+
+// baseTime has no sub-second part, the DATETIME columns do not keep it
+var baseTime = time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+
+func newArticle(i int, createdAt time.Time) domain.Article {
+	return domain.Article{
+		Title:     fmt.Sprintf("title %d", i),
+		Content:   fmt.Sprintf("content %d", i),
+		Author:    domain.Author{ID: int64(i%2 + 1)},
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
+
+func store(t *testing.T, repo domain.ArticleRepository, list ...domain.Article) []domain.Article {
+	stored := make([]domain.Article, 0, len(list))
+	for _, ar := range list {
+		require.NoError(t, repo.Store(context.TODO(), &ar))
+		require.NotZero(t, ar.ID, "Store must assign the id of the article")
+		stored = append(stored, ar)
+	}
+	return stored
+}
+
+func assertArticle(t *testing.T, expected, actual domain.Article) {
+	assert.Equal(t, expected.ID, actual.ID)
+	assert.Equal(t, expected.Title, actual.Title)
+	assert.Equal(t, expected.Content, actual.Content)
+	assert.Equal(t, expected.Author.ID, actual.Author.ID)
+	assert.True(t, expected.CreatedAt.Equal(actual.CreatedAt), "created_at: expected %s, actual %s", expected.CreatedAt, actual.CreatedAt)
+	assert.True(t, expected.UpdatedAt.Equal(actual.UpdatedAt), "updated_at: expected %s, actual %s", expected.UpdatedAt, actual.UpdatedAt)
+}
+
+func testStoreAndGet(t *testing.T, repo domain.ArticleRepository) {
+	list := store(t, repo, newArticle(1, baseTime), newArticle(2, baseTime))
+	assert.NotEqual(t, list[0].ID, list[1].ID)
+
+	for _, ar := range list {
+		res, err := repo.GetByID(context.TODO(), ar.ID)
+		require.NoError(t, err)
+		assertArticle(t, ar, res)
+
+		res, err = repo.GetByTitle(context.TODO(), ar.Title)
+		require.NoError(t, err)
+		assertArticle(t, ar, res)
+	}
+}
+
+func testGetNotFound(t *testing.T, repo domain.ArticleRepository) {
+	list := store(t, repo, newArticle(1, baseTime))
+
+	_, err := repo.GetByID(context.TODO(), list[0].ID+1)
+	assert.Equal(t, domain.ErrNotFound, err)
+
+	_, err = repo.GetByTitle(context.TODO(), "missing title")
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func testUpdate(t *testing.T, repo domain.ArticleRepository) {
+	list := store(t, repo, newArticle(1, baseTime), newArticle(2, baseTime))
+
+	ar := list[0]
+	ar.Title = "updated title"
+	ar.Content = "updated content"
+	ar.Author.ID = 9
+	ar.UpdatedAt = baseTime.Add(time.Hour)
+	require.NoError(t, repo.Update(context.TODO(), &ar))
+
+	res, err := repo.GetByID(context.TODO(), ar.ID)
+	require.NoError(t, err)
+	assertArticle(t, ar, res)
+
+	// the other article is left untouched
+	res, err = repo.GetByID(context.TODO(), list[1].ID)
+	require.NoError(t, err)
+	assertArticle(t, list[1], res)
+
+	missing := newArticle(3, baseTime)
+	missing.ID = list[1].ID + 1
+	assert.Equal(t, domain.ErrNotFound, repo.Update(context.TODO(), &missing))
+}
+
+// testDuplicatedTitle checks the titles are not unique in the storage, the usecase keeps them unique
+func testDuplicatedTitle(t *testing.T, repo domain.ArticleRepository) {
+	first, second := newArticle(1, baseTime), newArticle(2, baseTime)
+	second.Title = first.Title
+	store(t, repo, first, second)
+
+	res, err := repo.GetByTitle(context.TODO(), first.Title)
+	require.NoError(t, err)
+	assert.Equal(t, first.Title, res.Title)
+
+	third := store(t, repo, newArticle(3, baseTime))[0]
+	third.Title = first.Title
+	require.NoError(t, repo.Update(context.TODO(), &third))
+	res, err = repo.GetByID(context.TODO(), third.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first.Title, res.Title)
+}
+
+func testDelete(t *testing.T, repo domain.ArticleRepository) {
+	list := store(t, repo, newArticle(1, baseTime), newArticle(2, baseTime))
+
+	require.NoError(t, repo.Delete(context.TODO(), list[0].ID))
+	_, err := repo.GetByID(context.TODO(), list[0].ID)
+	assert.Equal(t, domain.ErrNotFound, err)
+	assert.Equal(t, domain.ErrNotFound, repo.Delete(context.TODO(), list[0].ID))
+
+	_, err = repo.GetByID(context.TODO(), list[1].ID)
+	assert.NoError(t, err)
+}
+
+func testFetchEmpty(t *testing.T, repo domain.ArticleRepository) {
+	list, nextCursor, prevCursor, err := repo.Fetch(context.TODO(), domain.ArticleFilter{}, "", 10,
+		domain.DirectionNext, domain.SortAsc)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+	assert.Empty(t, nextCursor)
+	assert.Empty(t, prevCursor)
+}
+
+// testNonPositiveNum asks for pages of no article, they must be empty whatever is stored
+func testNonPositiveNum(t *testing.T, repo domain.ArticleRepository) {
+	store(t, repo, newArticle(1, baseTime), newArticle(2, baseTime.Add(time.Minute)))
+
+	for _, num := range []int64{0, -1} {
+		list, nextCursor, prevCursor, err := repo.Fetch(context.TODO(), domain.ArticleFilter{}, "", num,
+			domain.DirectionNext, domain.SortAsc)
+		require.NoError(t, err)
+		assert.Empty(t, list, "num %d", num)
+		assert.Empty(t, nextCursor)
+		assert.Empty(t, prevCursor)
+
+		found, nextCursor, err := repo.Search(context.TODO(), "title", "", num)
+		require.NoError(t, err)
+		assert.Empty(t, found, "num %d", num)
+		assert.Empty(t, nextCursor)
+	}
+}
+
+func testFetchFilter(t *testing.T, repo domain.ArticleRepository) {
+	list := store(t, repo,
+		newArticle(1, baseTime),
+		newArticle(2, baseTime.Add(time.Minute)),
+		newArticle(3, baseTime.Add(2*time.Minute)),
+		newArticle(4, baseTime.Add(3*time.Minute)),
+	)
+
+	filter := domain.ArticleFilter{
+		AuthorID:    2,
+		CreatedFrom: baseTime.Add(time.Minute),
+	}
+	res, _, _, err := repo.Fetch(context.TODO(), filter, "", 10, domain.DirectionNext, domain.SortAsc)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{list[2].ID}, ids(res))
+
+	filter = domain.ArticleFilter{
+		CreatedFrom: baseTime.Add(time.Minute),
+		CreatedTo:   baseTime.Add(3 * time.Minute),
+	}
+	res, _, _, err = repo.Fetch(context.TODO(), filter, "", 10, domain.DirectionNext, domain.SortAsc)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{list[1].ID, list[2].ID}, ids(res))
+
+	filter = domain.ArticleFilter{TitlePrefix: "title 4"}
+	res, _, _, err = repo.Fetch(context.TODO(), filter, "", 10, domain.DirectionNext, domain.SortAsc)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{list[3].ID}, ids(res))
+}
+
+// testPaginate walks the whole list forward then backward, every article must be returned
+// exactly once and in the order of (sortBy, id)
+func testPaginate(t *testing.T, repo domain.ArticleRepository, sortBy domain.ArticleSortField, order domain.SortOrder) {
+	// several articles share the same timestamps, the id breaks the tie
+	offsets := []time.Duration{0, time.Minute, time.Minute, time.Minute, 2 * time.Minute, 3 * time.Minute, 3 * time.Minute}
+	articles := make([]domain.Article, 0, len(offsets))
+	for i, offset := range offsets {
+		ar := newArticle(i, baseTime.Add(offset))
+		// updated_at runs in the opposite direction of created_at
+		ar.UpdatedAt = baseTime.Add(-offset)
+		articles = append(articles, ar)
+	}
+	articles = store(t, repo, articles...)
+
+	expected := make([]domain.Article, len(articles))
+	copy(expected, articles)
+	sort.SliceStable(expected, func(i, j int) bool {
+		vi, vj := sortValue(expected[i], sortBy), sortValue(expected[j], sortBy)
+		if !vi.Equal(vj) {
+			return vi.Before(vj) == (order == domain.SortAsc)
+		}
+		return (expected[i].ID < expected[j].ID) == (order == domain.SortAsc)
+	})
+
+	const num = 3
+	filter := domain.ArticleFilter{SortBy: sortBy}
+
+	pages := make([][]int64, 0)
+	cursor, prevCursor := "", ""
+	for i := 0; ; i++ {
+		require.True(t, i < maxPages, "the forward walk does not end")
+
+		res, nextCursor, prev, err := repo.Fetch(context.TODO(), filter, cursor, num, domain.DirectionNext, order)
+		require.NoError(t, err)
+		require.True(t, len(res) <= num, "the page must hold at most %d articles", num)
+		if i == 0 {
+			assert.Empty(t, prev, "the first page has no previous page")
+		}
+		if len(res) > 0 {
+			pages = append(pages, ids(res))
+			prevCursor = prev
+		}
+		if nextCursor == "" {
+			break
+		}
+		cursor = nextCursor
+	}
+	assert.Equal(t, ids(expected), flatten(pages), "the forward walk must return every article once, in order")
+
+	backward := make([][]int64, 0)
+	cursor = prevCursor
+	for i := 0; cursor != ""; i++ {
+		require.True(t, i < maxPages, "the backward walk does not end")
+
+		res, _, prev, err := repo.Fetch(context.TODO(), filter, cursor, num, domain.DirectionPrev, order)
+		require.NoError(t, err)
+		require.True(t, len(res) <= num, "the page must hold at most %d articles", num)
+		if len(res) == 0 {
+			break
+		}
+		// the page keeps the requested order even when it is fetched backward
+		backward = append([][]int64{ids(res)}, backward...)
+		cursor = prev
+	}
+	if len(pages) > 0 {
+		assert.Equal(t, pages[:len(pages)-1], backward, "the backward walk must return the same pages as the forward walk")
+	}
+}
+
+func sortValue(ar domain.Article, sortBy domain.ArticleSortField) time.Time {
+	if sortBy == domain.ArticleSortUpdatedAt {
+		return ar.UpdatedAt
+	}
+	return ar.CreatedAt
+}
+
+func ids(list []domain.Article) []int64 {
+	res := make([]int64, 0, len(list))
+	for _, ar := range list {
+		res = append(res, ar.ID)
+	}
+	return res
+}
+
+func flatten(pages [][]int64) []int64 {
+	res := make([]int64, 0)
+	for _, page := range pages {
+		res = append(res, page...)
+	}
+	return res
+}
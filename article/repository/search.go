@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"encoding/base64"
+	"html"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+)
+
+const (
+	searchCursorVersion = "s1"
+
+	snippetRadius  = 60 // number of runes kept on each side of the first match
+	highlightOpen  = "<mark>"
+	highlightClose = "</mark>"
+)
+
+// SearchCursor represents the position of an article in a list ordered by (score DESC, id DESC)
+type SearchCursor struct {
+	Score float64
+	ID    int64
+}
+
+// DecodeSearchCursor will decode the search cursor from user
+func DecodeSearchCursor(encodedCursor string) (SearchCursor, error) {
+	byt, err := base64.StdEncoding.DecodeString(encodedCursor)
+	if err != nil {
+		return SearchCursor{}, err
+	}
+
+	parts := strings.Split(string(byt), cursorSeparator)
+	if len(parts) != 3 || parts[0] != searchCursorVersion {
+		return SearchCursor{}, ErrInvalidCursor
+	}
+
+	score, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return SearchCursor{}, err
+	}
+	id, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return SearchCursor{}, err
+	}
+
+	return SearchCursor{Score: score, ID: id}, nil
+}
+
+// EncodeSearchCursor will encode the search cursor to user
+func EncodeSearchCursor(c SearchCursor) string {
+	cursorString := strings.Join([]string{
+		searchCursorVersion,
+		strconv.FormatFloat(c.Score, 'g', -1, 64),
+		strconv.FormatInt(c.ID, 10),
+	}, cursorSeparator)
+
+	return base64.StdEncoding.EncodeToString([]byte(cursorString))
+}
+
+// SearchTerms splits the search query into the lower-cased words used for highlighting
+func SearchTerms(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	seen := map[string]bool{}
+	for _, w := range words {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+		terms = append(terms, w)
+	}
+	return terms
+}
+
+// SearchScore ranks the article by the number of occurrences of the search terms,
+// a match in the title weighs twice as much as a match in the content
+func SearchScore(ar domain.Article, terms []string) float64 {
+	title := SearchTerms(ar.Title)
+	content := SearchTerms(ar.Content)
+	score := 0.0
+	for _, term := range terms {
+		score += 2 * float64(countTerm(title, term))
+		score += float64(countTerm(content, term))
+	}
+	return score
+}
+
+func countTerm(words []string, term string) (n int) {
+	for _, w := range words {
+		if w == term {
+			n++
+		}
+	}
+	return
+}
+
+// Snippet returns an HTML-escaped excerpt of text around the first matched term,
+// every whole-word match of a term in the excerpt is wrapped with <mark></mark>
+func Snippet(text string, terms []string) string {
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(runes) {
+		// lower-casing changed the length, fall back to case-sensitive matching
+		lower = runes
+	}
+
+	type match struct{ start, end int }
+	matches := make([]match, 0)
+	for i := 0; i < len(lower); {
+		matched := 0
+		if i == 0 || !isWordRune(lower[i-1]) {
+			for _, term := range terms {
+				n := utf8.RuneCountInString(term)
+				if n > matched && hasPrefixAt(lower, i, term) && (i+n == len(lower) || !isWordRune(lower[i+n])) {
+					matched = n
+				}
+			}
+		}
+		if matched > 0 {
+			matches = append(matches, match{i, i + matched})
+			i += matched
+			continue
+		}
+		i++
+	}
+
+	start, end := 0, len(runes)
+	if len(matches) > 0 {
+		start = matches[0].start - snippetRadius
+		end = matches[0].end + snippetRadius
+	} else {
+		end = 2 * snippetRadius
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, m := range matches {
+		if m.start < start || m.end > end {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:m.start])))
+		b.WriteString(highlightOpen)
+		b.WriteString(html.EscapeString(string(runes[m.start:m.end])))
+		b.WriteString(highlightClose)
+		pos = m.end
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+func hasPrefixAt(text []rune, i int, term string) bool {
+	for _, r := range term {
+		if i >= len(text) || text[i] != r {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
@@ -0,0 +1,45 @@
+package repository_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/phantomnat/go-clean-architecture/article/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchCursor(t *testing.T) {
+	c := repository.SearchCursor{Score: 0.12345678901234, ID: 7}
+
+	decoded, err := repository.DecodeSearchCursor(repository.EncodeSearchCursor(c))
+	assert.NoError(t, err)
+	assert.Equal(t, c, decoded)
+
+	_, err = repository.DecodeSearchCursor(repository.EncodeCursor(repository.Cursor{ID: 7}))
+	assert.Equal(t, repository.ErrInvalidCursor, err)
+}
+
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"clean", "architecture", "in", "go"}, repository.SearchTerms("Clean architecture, in GO! clean"))
+}
+
+func TestSnippet(t *testing.T) {
+	t.Run("highlight whole words", func(t *testing.T) {
+		snippet := repository.Snippet("Go is good, go <fast>", []string{"go"})
+		assert.Equal(t, "<mark>Go</mark> is good, <mark>go</mark> &lt;fast&gt;", snippet)
+	})
+
+	t.Run("cut around the first match", func(t *testing.T) {
+		text := strings.Repeat("a ", 100) + "target" + strings.Repeat(" b", 100)
+		snippet := repository.Snippet(text, []string{"target"})
+
+		assert.True(t, strings.HasPrefix(snippet, "…"))
+		assert.True(t, strings.HasSuffix(snippet, "…"))
+		assert.Contains(t, snippet, "<mark>target</mark>")
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		assert.Equal(t, "hello", repository.Snippet("hello", []string{"world"}))
+	})
+}
@@ -0,0 +1,358 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/phantomnat/go-clean-architecture/article/repository"
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/logging"
+)
+
+type sqliteArticleRepository struct {
+	Conn *sql.DB
+}
+
+// NewSqliteArticleRepository will create an object that represent the article.Repository interface
+func NewSqliteArticleRepository(Conn *sql.DB) domain.ArticleRepository {
+	return &sqliteArticleRepository{Conn}
+}
+
+func (m *sqliteArticleRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Article, err error) {
+	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	result = make([]domain.Article, 0)
+	for rows.Next() {
+		t := domain.Article{}
+		authorID := int64(0)
+		err = rows.Scan(
+			&t.ID,
+			&t.Title,
+			&t.Content,
+			&authorID,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		t.Author = domain.Author{
+			ID: authorID,
+		}
+		result = append(result, t)
+	}
+
+	return result, nil
+}
+
+func (m *sqliteArticleRepository) Fetch(ctx context.Context, filter domain.ArticleFilter, cursor string, num int64,
+	direction domain.Direction, order domain.SortOrder) (res []domain.Article, nextCursor string, prevCursor string, err error) {
+	// a negative LIMIT means no limit to SQLite
+	if num <= 0 {
+		return make([]domain.Article, 0), "", "", nil
+	}
+
+	sortBy := filter.SortBy
+	if sortBy == "" {
+		sortBy = domain.ArticleSortCreatedAt
+	}
+	// the column name is interpolated into the query, only the known columns are allowed
+	if !sortBy.IsValid() {
+		return nil, "", "", domain.ErrBadParamInput
+	}
+
+	// the previous page is fetched by walking the list backward from the cursor
+	backward := direction == domain.DirectionPrev
+	ascending := (order != domain.SortDesc) != backward
+
+	conditions, args := filterConditions(filter)
+
+	if cursor != "" {
+		decodedCursor, err := repository.DecodeCursor(cursor)
+		if err != nil || decodedCursor.SortBy != sortBy {
+			return nil, "", "", domain.ErrBadParamInput
+		}
+
+		if ascending {
+			conditions = append(conditions, fmt.Sprintf("(%s, id) > (?, ?)", sortBy))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("(%s, id) < (?, ?)", sortBy))
+		}
+		args = append(args, decodedCursor.Value.UTC(), decodedCursor.ID)
+	}
+
+	query := `SELECT id,title,content, author_id, updated_at, created_at FROM article`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	if ascending {
+		query += fmt.Sprintf(` ORDER BY %s, id LIMIT ?`, sortBy)
+	} else {
+		query += fmt.Sprintf(` ORDER BY %s DESC, id DESC LIMIT ?`, sortBy)
+	}
+	args = append(args, num)
+
+	res, err = m.fetch(ctx, query, args...)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	if backward {
+		reverse(res)
+	}
+
+	if len(res) == 0 {
+		return
+	}
+
+	first, last := res[0], res[len(res)-1]
+	hasMore := len(res) == int(num)
+	if (hasMore && !backward) || (backward && cursor != "") {
+		nextCursor = repository.EncodeCursor(repository.NewCursor(last, sortBy))
+	}
+	if (hasMore && backward) || (!backward && cursor != "") {
+		prevCursor = repository.EncodeCursor(repository.NewCursor(first, sortBy))
+	}
+
+	return
+}
+
+// filterConditions builds the parameterised WHERE conditions of given filter.
+// The timestamps are stored as text in UTC, so every time param is converted to UTC to keep them comparable.
+func filterConditions(filter domain.ArticleFilter) (conditions []string, args []interface{}) {
+	conditions = make([]string, 0)
+	args = make([]interface{}, 0)
+
+	if filter.AuthorID != 0 {
+		conditions = append(conditions, "author_id = ?")
+		args = append(args, filter.AuthorID)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.CreatedFrom.UTC())
+	}
+	if !filter.CreatedTo.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.CreatedTo.UTC())
+	}
+	if !filter.UpdatedFrom.IsZero() {
+		conditions = append(conditions, "updated_at >= ?")
+		args = append(args, filter.UpdatedFrom.UTC())
+	}
+	if !filter.UpdatedTo.IsZero() {
+		conditions = append(conditions, "updated_at < ?")
+		args = append(args, filter.UpdatedTo.UTC())
+	}
+	if filter.TitlePrefix != "" {
+		conditions = append(conditions, `title LIKE ? ESCAPE '\'`)
+		args = append(args, repository.EscapeLike(filter.TitlePrefix)+"%")
+	}
+
+	return
+}
+
+func reverse(list []domain.Article) {
+	for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+		list[i], list[j] = list[j], list[i]
+	}
+}
+
+func (m *sqliteArticleRepository) GetByID(ctx context.Context, id int64) (res domain.Article, err error) {
+	query := `SELECT id,title,content, author_id, updated_at, created_at
+  						FROM article WHERE ID = ?`
+
+	list, err := m.fetch(ctx, query, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+
+	return
+}
+
+func (m *sqliteArticleRepository) GetByTitle(ctx context.Context, title string) (res domain.Article, err error) {
+	query := `SELECT id,title,content, author_id, updated_at, created_at
+  						FROM article WHERE title = ?`
+
+	list, err := m.fetch(ctx, query, title)
+	if err != nil {
+		return
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+	return
+}
+
+// Search has no full-text index to rely on, the articles containing any of the search terms are
+// ranked in memory by the number of occurrences, a match in the title weighs twice as much.
+func (m *sqliteArticleRepository) Search(ctx context.Context, q string, cursor string, num int64) (res []domain.ArticleSearchResult, nextCursor string, err error) {
+	if num <= 0 {
+		return make([]domain.ArticleSearchResult, 0), "", nil
+	}
+
+	var decodedCursor repository.SearchCursor
+	if cursor != "" {
+		decodedCursor, err = repository.DecodeSearchCursor(cursor)
+		if err != nil {
+			return nil, "", domain.ErrBadParamInput
+		}
+	}
+
+	terms := repository.SearchTerms(q)
+	if len(terms) == 0 {
+		return make([]domain.ArticleSearchResult, 0), "", nil
+	}
+
+	conditions := make([]string, 0, len(terms))
+	args := make([]interface{}, 0, 2*len(terms))
+	for _, term := range terms {
+		pattern := "%" + repository.EscapeLike(term) + "%"
+		conditions = append(conditions, `title LIKE ? ESCAPE '\' OR content LIKE ? ESCAPE '\'`)
+		args = append(args, pattern, pattern)
+	}
+	query := `SELECT id,title,content, author_id, updated_at, created_at
+  						FROM article WHERE ` + strings.Join(conditions, " OR ")
+
+	list, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	res = make([]domain.ArticleSearchResult, 0)
+	for _, ar := range list {
+		score := repository.SearchScore(ar, terms)
+		if score == 0 {
+			continue
+		}
+		if cursor != "" && (score > decodedCursor.Score || (score == decodedCursor.Score && ar.ID >= decodedCursor.ID)) {
+			continue
+		}
+		res = append(res, domain.ArticleSearchResult{
+			Article: ar,
+			Score:   score,
+			Snippet: repository.Snippet(ar.Content, terms),
+		})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		return res[i].ID > res[j].ID
+	})
+
+	if len(res) > int(num) {
+		res = res[:num]
+	}
+	if len(res) == int(num) {
+		last := res[len(res)-1]
+		nextCursor = repository.EncodeSearchCursor(repository.SearchCursor{Score: last.Score, ID: last.ID})
+	}
+
+	return
+}
+
+func (m *sqliteArticleRepository) Store(ctx context.Context, a *domain.Article) (err error) {
+	query := `INSERT INTO article (title, content, author_id, updated_at, created_at) VALUES (?, ?, ?, ?, ?)`
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, a.Title, a.Content, a.Author.ID, a.UpdatedAt.UTC(), a.CreatedAt.UTC())
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	a.ID = lastID
+	return
+}
+
+func (m *sqliteArticleRepository) Delete(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM article WHERE id = ?"
+
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+
+	rowsAfected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAfected == 0 {
+		err = domain.ErrNotFound
+		return
+	}
+	if rowsAfected != 1 {
+		err = fmt.Errorf("Weird  Behaviour. Total Affected: %d", rowsAfected)
+		return
+	}
+
+	return
+}
+
+func (m *sqliteArticleRepository) Update(ctx context.Context, ar *domain.Article) (err error) {
+	query := `UPDATE article set title=?, content=?, author_id=?, updated_at=? WHERE ID = ?`
+
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt.UTC(), ar.ID)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect == 0 {
+		err = domain.ErrNotFound
+		return
+	}
+	if affect != 1 {
+		err = fmt.Errorf("Weird  Behaviour. Total Affected: %d", affect)
+		return
+	}
+
+	return
+}
@@ -0,0 +1,172 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/article/repository/repositorytest"
+	"github.com/phantomnat/go-clean-architecture/article/repository/sqlite"
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/migration"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	// every connection of an in-memory database is a separate database
+	db.SetMaxOpenConns(1)
+	m, err := migration.NewMigrator(db, "sqlite3")
+	require.NoError(t, err)
+	_, err = m.Up(context.TODO())
+	require.NoError(t, err)
+	return db
+}
+
+func storeArticles(t *testing.T, a domain.ArticleRepository, createdAt []time.Time) []domain.Article {
+	list := make([]domain.Article, 0, len(createdAt))
+	for i, c := range createdAt {
+		ar := domain.Article{
+			Title:     "title " + string(rune('a'+i)),
+			Content:   "content",
+			Author:    domain.Author{ID: 1},
+			CreatedAt: c,
+			UpdatedAt: c,
+		}
+		require.NoError(t, a.Store(context.TODO(), &ar))
+		list = append(list, ar)
+	}
+	return list
+}
+
+func ids(list []domain.Article) []int64 {
+	res := make([]int64, 0, len(list))
+	for _, ar := range list {
+		res = append(res, ar.ID)
+	}
+	return res
+}
+
+func TestFetch(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+	a := sqlite.NewSqliteArticleRepository(db)
+
+	now := time.Now()
+	// the second and the third articles share the same created_at
+	storeArticles(t, a, []time.Time{now, now.Add(time.Second), now.Add(time.Second), now.Add(2 * time.Second)})
+
+	list, nextCursor, prevCursor, err := a.Fetch(context.TODO(), domain.ArticleFilter{}, "", 2, domain.DirectionNext, domain.SortAsc)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, ids(list))
+	assert.Empty(t, prevCursor)
+
+	list, nextCursor, prevCursor, err = a.Fetch(context.TODO(), domain.ArticleFilter{}, nextCursor, 2, domain.DirectionNext, domain.SortAsc)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 4}, ids(list))
+	assert.NotEmpty(t, nextCursor)
+
+	list, _, _, err = a.Fetch(context.TODO(), domain.ArticleFilter{}, prevCursor, 2, domain.DirectionPrev, domain.SortAsc)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, ids(list))
+
+	list, nextCursor, _, err = a.Fetch(context.TODO(), domain.ArticleFilter{}, "", 3, domain.DirectionNext, domain.SortDesc)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{4, 3, 2}, ids(list))
+
+	list, _, _, err = a.Fetch(context.TODO(), domain.ArticleFilter{}, nextCursor, 3, domain.DirectionNext, domain.SortDesc)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, ids(list))
+}
+
+func TestFetchWithFilter(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+	a := sqlite.NewSqliteArticleRepository(db)
+
+	now := time.Now()
+	storeArticles(t, a, []time.Time{now, now.Add(time.Second), now.Add(2 * time.Second)})
+
+	filter := domain.ArticleFilter{
+		CreatedFrom: now.Add(time.Second),
+		TitlePrefix: "title",
+	}
+	list, _, _, err := a.Fetch(context.TODO(), filter, "", 10, domain.DirectionNext, domain.SortAsc)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, ids(list))
+
+	filter = domain.ArticleFilter{TitlePrefix: "title_"}
+	list, _, _, err = a.Fetch(context.TODO(), filter, "", 10, domain.DirectionNext, domain.SortAsc)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestSearch(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+	a := sqlite.NewSqliteArticleRepository(db)
+
+	now := time.Now()
+	for _, ar := range []domain.Article{
+		{Title: "Clean architecture", Content: "Trying clean architecture in Go", CreatedAt: now, UpdatedAt: now},
+		{Title: "Go routines", Content: "Concurrency in Go", CreatedAt: now, UpdatedAt: now},
+		{Title: "Cooking", Content: "Nothing to see", CreatedAt: now, UpdatedAt: now},
+	} {
+		ar := ar
+		require.NoError(t, a.Store(context.TODO(), &ar))
+	}
+
+	list, nextCursor, err := a.Search(context.TODO(), "clean", "", 1)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, int64(1), list[0].ID)
+	assert.Equal(t, "Trying <mark>clean</mark> architecture in Go", list[0].Snippet)
+
+	list, _, err = a.Search(context.TODO(), "clean", nextCursor, 1)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	list, _, err = a.Search(context.TODO(), "go", "", 10)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, int64(2), list[0].ID)
+}
+
+func TestCRUD(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+	a := sqlite.NewSqliteArticleRepository(db)
+
+	ar := storeArticles(t, a, []time.Time{time.Now()})[0]
+
+	res, err := a.GetByTitle(context.TODO(), ar.Title)
+	require.NoError(t, err)
+	assert.Equal(t, ar.ID, res.ID)
+	assert.True(t, ar.CreatedAt.Equal(res.CreatedAt))
+
+	ar.Title = "updated"
+	require.NoError(t, a.Update(context.TODO(), &ar))
+	res, err = a.GetByID(context.TODO(), ar.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "updated", res.Title)
+
+	require.NoError(t, a.Delete(context.TODO(), ar.ID))
+	_, err = a.GetByID(context.TODO(), ar.ID)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestContract(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+
+	repositorytest.RunArticleRepositoryTests(t, func(t *testing.T) domain.ArticleRepository {
+		_, err := db.Exec("DELETE FROM article")
+		require.NoError(t, err)
+		return sqlite.NewSqliteArticleRepository(db)
+	})
+}
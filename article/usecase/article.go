@@ -2,116 +2,129 @@ package usecase
 
 import (
 	"context"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/logging"
 
 	"github.com/sirupsen/logrus"
-	"golang.org/x/sync/errgroup"
 )
 
 type articleUsecase struct {
+	// contextTimeout is a time.Duration, it is accessed atomically so it can be changed while serving
+	contextTimeout int64
 	articleRepo    domain.ArticleRepository
 	authorRepo     domain.AuthorRepository
-	contextTimeout time.Duration
+	policy         domain.ArticlePolicy
 }
 
 var _ domain.ArticleUsecase = &articleUsecase{}
 
-type AuthorChannel struct {
-	Author *domain.Author
-	Error  error
-}
-
-// NewArticleUseCase will create new articleUsecase object representation of domain.ArticleUseCase interface
-func NewArticleUseCase(article domain.ArticleRepository, author domain.AuthorRepository, timeout time.Duration) domain.ArticleUsecase {
+// NewArticleUseCase will create new articleUsecase object representation of domain.ArticleUseCase interface,
+// the policy authorizes the principal of the context to read and change the articles
+func NewArticleUseCase(article domain.ArticleRepository, author domain.AuthorRepository, policy domain.ArticlePolicy,
+	timeout time.Duration) domain.ArticleUsecase {
 	return &articleUsecase{
 		articleRepo:    article,
 		authorRepo:     author,
-		contextTimeout: timeout,
+		policy:         policy,
+		contextTimeout: int64(timeout),
 	}
 }
 
-func (a *articleUsecase) fillAuthorDetails(c context.Context, data []domain.Article) ([]domain.Article, error) {
-	g, ctx := errgroup.WithContext(c)
+// SetContextTimeout changes the timeout of the next calls, the calls in flight keep theirs
+func (a *articleUsecase) SetContextTimeout(timeout time.Duration) {
+	atomic.StoreInt64(&a.contextTimeout, int64(timeout))
+}
 
-	mapAuthors := map[int64]domain.Author{}
+func (a *articleUsecase) timeout() time.Duration {
+	return time.Duration(atomic.LoadInt64(&a.contextTimeout))
+}
 
+func (a *articleUsecase) fillAuthorDetails(ctx context.Context, data []domain.Article) ([]domain.Article, error) {
+	authorIDs := make([]int64, 0)
+	seen := map[int64]bool{}
 	for _, article := range data {
-		mapAuthors[article.Author.ID] = domain.Author{}
-	}
-
-	// Using goroutine to fetch the author's detail
-	chanAuthor := make(chan domain.Author)
-	for authorID := range mapAuthors {
-		aID := authorID
-		g.Go(func() error {
-			res, err := a.authorRepo.GetByID(ctx, aID)
-			if err != nil {
-				return err
-			}
-			chanAuthor <- res
-			return nil
-		})
-	}
-
-	go func() {
-		err := g.Wait()
-		if err != nil {
-			logrus.Error(err)
-			return
-		}
-		close(chanAuthor)
-	}()
-
-	for author := range chanAuthor {
-		if author != (domain.Author{}) {
-			mapAuthors[author.ID] = author
+		if seen[article.Author.ID] {
+			continue
 		}
+		seen[article.Author.ID] = true
+		authorIDs = append(authorIDs, article.Author.ID)
 	}
 
-	if err := g.Wait(); err != nil {
+	// fetch every author's detail with a single query
+	mapAuthors, err := a.authorRepo.GetByIDs(ctx, authorIDs)
+	if err != nil {
 		return nil, err
 	}
 
 	// merge the author's data
 	for index, item := range data {
-		if author, ok := mapAuthors[item.Author.ID]; ok {
-			data[index].Author = author
+		author, ok := mapAuthors[item.Author.ID]
+		if !ok {
+			logging.FromContext(ctx).WithFields(logrus.Fields{
+				"article_id": item.ID,
+				"author_id":  item.Author.ID,
+			}).Warn("author of the article not found")
+			continue
 		}
+		data[index].Author = author
 	}
 
 	return data, nil
 }
 
-func (a *articleUsecase) Fetch(c context.Context, cursor string, num int64) (res []domain.Article, nextCursor string, err error) {
+func (a *articleUsecase) Fetch(c context.Context, filter domain.ArticleFilter, cursor string, num int64,
+	direction domain.Direction, order domain.SortOrder) (res []domain.Article, nextCursor string, prevCursor string, err error) {
+
+	if num < 0 {
+		return nil, "", "", domain.ErrBadParamInput
+	}
 	if num == 0 {
 		num = 10
 	}
+	if direction == "" {
+		direction = domain.DirectionNext
+	}
+	if order == "" {
+		order = domain.SortAsc
+	}
+	if filter.SortBy == "" {
+		filter.SortBy = domain.ArticleSortCreatedAt
+	}
+	if !direction.IsValid() || !order.IsValid() || !filter.SortBy.IsValid() {
+		return nil, "", "", domain.ErrBadParamInput
+	}
 
-	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	ctx, cancel := context.WithTimeout(c, a.timeout())
 	defer cancel()
 
-	res, nextCursor, err = a.articleRepo.Fetch(ctx, cursor, num)
+	res, nextCursor, prevCursor, err = a.articleRepo.Fetch(ctx, filter, cursor, num, direction, order)
 	if err != nil {
-		return nil, "", err
+		return nil, "", "", err
 	}
 
 	res, err = a.fillAuthorDetails(ctx, res)
 	if err != nil {
 		nextCursor = ""
+		prevCursor = ""
 	}
 	return
 }
 
 func (a *articleUsecase) GetByID(c context.Context, id int64) (res domain.Article, err error) {
-	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	ctx, cancel := context.WithTimeout(c, a.timeout())
 	defer cancel()
 
 	res, err = a.articleRepo.GetByID(ctx, id)
 	if err != nil {
 		return
 	}
+	if err = a.policy.Authorize(ctx, domain.ArticleRead, res); err != nil {
+		return domain.Article{}, err
+	}
 
 	resAuthor, err := a.authorRepo.GetByID(ctx, res.Author.ID)
 	if err != nil {
@@ -123,15 +136,44 @@ func (a *articleUsecase) GetByID(c context.Context, id int64) (res domain.Articl
 }
 
 func (a *articleUsecase) Update(c context.Context, ar *domain.Article) error {
-	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	ctx, cancel := context.WithTimeout(c, a.timeout())
 	defer cancel()
 
+	existedArticle, err := a.articleRepo.GetByID(ctx, ar.ID)
+	if err != nil {
+		return err
+	}
+	if err = a.policy.Authorize(ctx, domain.ArticleUpdate, existedArticle); err != nil {
+		return err
+	}
+	// moving the article to another author requires the update right on the articles of the latter
+	if ar.Author.ID != existedArticle.Author.ID {
+		if err = a.policy.Authorize(ctx, domain.ArticleUpdate, *ar); err != nil {
+			return err
+		}
+	}
+	if ar.Title != existedArticle.Title {
+		other, err := a.articleRepo.GetByTitle(ctx, ar.Title)
+		if err == nil && other.ID != ar.ID {
+			return domain.ErrAlreadyExist
+		}
+		if err != nil && err != domain.ErrNotFound {
+			return err
+		}
+	}
+
+	ar.CreatedAt = existedArticle.CreatedAt
 	ar.UpdatedAt = time.Now()
-	return a.articleRepo.Update(ctx, ar)
+	err = a.articleRepo.Update(ctx, ar)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).WithField("article_id", ar.ID).Debug("article updated")
+	return nil
 }
 
 func (a *articleUsecase) GetByTitle(c context.Context, title string) (res domain.Article, err error) {
-	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	ctx, cancel := context.WithTimeout(c, a.timeout())
 	defer cancel()
 
 	res, err = a.articleRepo.GetByTitle(ctx, title)
@@ -148,8 +190,42 @@ func (a *articleUsecase) GetByTitle(c context.Context, title string) (res domain
 	return res, nil
 }
 
+func (a *articleUsecase) Search(c context.Context, query string, cursor string, num int64) (res []domain.ArticleSearchResult, nextCursor string, err error) {
+	if strings.TrimSpace(query) == "" || num < 0 {
+		return nil, "", domain.ErrBadParamInput
+	}
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.timeout())
+	defer cancel()
+
+	res, nextCursor, err = a.articleRepo.Search(ctx, query, cursor, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	articles := make([]domain.Article, len(res))
+	for i, item := range res {
+		articles[i] = item.Article
+	}
+	articles, err = a.fillAuthorDetails(ctx, articles)
+	if err != nil {
+		return nil, "", err
+	}
+	for i := range res {
+		res[i].Article = articles[i]
+	}
+	return
+}
+
 func (a *articleUsecase) Store(c context.Context, ar *domain.Article) (err error) {
-	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	if err = a.policy.Authorize(c, domain.ArticleCreate, *ar); err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.timeout())
 	defer cancel()
 
 	existedArticle, _ := a.GetByTitle(ctx, ar.Title)
@@ -157,12 +233,19 @@ func (a *articleUsecase) Store(c context.Context, ar *domain.Article) (err error
 		return domain.ErrAlreadyExist
 	}
 
+	now := time.Now()
+	ar.CreatedAt = now
+	ar.UpdatedAt = now
 	err = a.articleRepo.Store(ctx, ar)
+	if err != nil {
+		return
+	}
+	logging.FromContext(ctx).WithField("article_id", ar.ID).Debug("article stored")
 	return
 }
 
 func (a *articleUsecase) Delete(c context.Context, id int64) (err error) {
-	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	ctx, cancel := context.WithTimeout(c, a.timeout())
 	defer cancel()
 
 	existedArticle, err := a.articleRepo.GetByID(ctx, id)
@@ -172,5 +255,13 @@ func (a *articleUsecase) Delete(c context.Context, id int64) (err error) {
 	if existedArticle == (domain.Article{}) {
 		return domain.ErrNotFound
 	}
-	return a.articleRepo.Delete(ctx, id)
+	if err = a.policy.Authorize(ctx, domain.ArticleDelete, existedArticle); err != nil {
+		return
+	}
+	err = a.articleRepo.Delete(ctx, id)
+	if err != nil {
+		return
+	}
+	logging.FromContext(ctx).WithField("article_id", id).Debug("article deleted")
+	return
 }
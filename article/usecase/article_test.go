@@ -6,7 +6,9 @@ import (
 	"testing"
 	"time"
 
+	articleMemory "github.com/phantomnat/go-clean-architecture/article/repository/memory"
 	"github.com/phantomnat/go-clean-architecture/article/usecase"
+	authorMemory "github.com/phantomnat/go-clean-architecture/author/repository/memory"
 	"github.com/phantomnat/go-clean-architecture/domain"
 	"github.com/phantomnat/go-clean-architecture/domain/mocks"
 
@@ -29,21 +31,23 @@ func TestFetch(t *testing.T) {
 	//})
 
 	t.Run("success", func(t *testing.T) {
-		mockArticleRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("int64")).
-			Return(mockListArticle, "next-cursor", nil).Once()
+		mockArticleRepo.On("Fetch", mock.Anything, domain.ArticleFilter{SortBy: domain.ArticleSortCreatedAt}, mock.AnythingOfType("string"), mock.AnythingOfType("int64"),
+			domain.DirectionNext, domain.SortAsc).Return(mockListArticle, "next-cursor", "prev-cursor", nil).Once()
 		mockAuthor := domain.Author{
 			ID:   1,
 			Name: "Iron Man",
 		}
 		mockAuthorRepo := new(mocks.AuthorRepository)
-		mockAuthorRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockAuthor, nil)
-		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, time.Second*2)
+		mockAuthorRepo.On("GetByIDs", mock.Anything, []int64{0}).
+			Return(map[int64]domain.Author{0: mockAuthor}, nil).Once()
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
 		num := int64(1)
 		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
+		list, nextCursor, prevCursor, err := u.Fetch(context.TODO(), domain.ArticleFilter{}, cursor, num, "", "")
 		cursorExpected := "next-cursor"
 
 		assert.Equal(t, cursorExpected, nextCursor)
+		assert.Equal(t, "prev-cursor", prevCursor)
 		assert.NotEmpty(t, nextCursor)
 		assert.NoError(t, err)
 		assert.Len(t, list, len(mockListArticle))
@@ -51,14 +55,30 @@ func TestFetch(t *testing.T) {
 		mockArticleRepo.AssertExpectations(t)
 		mockAuthorRepo.AssertExpectations(t)
 	})
+	t.Run("error-author-failed", func(t *testing.T) {
+		mockArticleRepo.On("Fetch", mock.Anything, domain.ArticleFilter{SortBy: domain.ArticleSortCreatedAt}, mock.AnythingOfType("string"), mock.AnythingOfType("int64"),
+			domain.DirectionNext, domain.SortAsc).Return(mockListArticle, "next-cursor", "prev-cursor", nil).Once()
+		mockAuthorRepo := new(mocks.AuthorRepository)
+		mockAuthorRepo.On("GetByIDs", mock.Anything, mock.AnythingOfType("[]int64")).
+			Return(nil, errors.New("unexpected error")).Once()
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
+		list, nextCursor, prevCursor, err := u.Fetch(context.TODO(), domain.ArticleFilter{}, "12", int64(1), "", "")
+
+		assert.Empty(t, nextCursor)
+		assert.Empty(t, prevCursor)
+		assert.Error(t, err)
+		assert.Len(t, list, 0)
+		mockArticleRepo.AssertExpectations(t)
+		mockAuthorRepo.AssertExpectations(t)
+	})
 	t.Run("error-failed", func(t *testing.T) {
-		mockArticleRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("int64")).
-			Return(nil, "", errors.New("unexpected error")).Once()
+		mockArticleRepo.On("Fetch", mock.Anything, domain.ArticleFilter{SortBy: domain.ArticleSortCreatedAt}, mock.AnythingOfType("string"), mock.AnythingOfType("int64"),
+			domain.DirectionNext, domain.SortAsc).Return(nil, "", "", errors.New("unexpected error")).Once()
 		mockAuthorRepo := new(mocks.AuthorRepository)
-		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, time.Second*2)
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
 		num := int64(1)
 		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
+		list, nextCursor, _, err := u.Fetch(context.TODO(), domain.ArticleFilter{}, cursor, num, "", "")
 
 		assert.Empty(t, nextCursor)
 		assert.Error(t, err)
@@ -66,6 +86,22 @@ func TestFetch(t *testing.T) {
 		mockArticleRepo.AssertExpectations(t)
 		mockAuthorRepo.AssertExpectations(t)
 	})
+	t.Run("error-invalid-order", func(t *testing.T) {
+		mockAuthorRepo := new(mocks.AuthorRepository)
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
+		_, _, _, err := u.Fetch(context.TODO(), domain.ArticleFilter{}, "", 1, domain.DirectionNext, "random")
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("error-negative-num", func(t *testing.T) {
+		mockAuthorRepo := new(mocks.AuthorRepository)
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
+		_, _, _, err := u.Fetch(context.TODO(), domain.ArticleFilter{}, "", -1, "", "")
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
 }
 
 func TestGetByID(t *testing.T) {
@@ -83,7 +119,7 @@ func TestGetByID(t *testing.T) {
 		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockArticle, nil).Once()
 		mockAuthorRepo := new(mocks.AuthorRepository)
 		mockAuthorRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockAuthor, nil)
-		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, time.Second*2)
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
 		a, err := u.GetByID(context.TODO(), mockArticle.ID)
 
 		assert.NoError(t, err)
@@ -97,7 +133,7 @@ func TestGetByID(t *testing.T) {
 		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).
 			Return(domain.Article{}, errors.New("unexpected error")).Once()
 		mockAuthorRepo := new(mocks.AuthorRepository)
-		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, time.Second*2)
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
 		a, err := u.GetByID(context.TODO(), mockArticle.ID)
 		assert.Error(t, err)
 		assert.Equal(t, domain.Article{}, a)
@@ -106,6 +142,26 @@ func TestGetByID(t *testing.T) {
 	})
 }
 
+func TestSetContextTimeout(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockAuthorRepo := new(mocks.AuthorRepository)
+	var deadlines []time.Duration
+	mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Run(func(args mock.Arguments) {
+		deadline, ok := args.Get(0).(context.Context).Deadline()
+		assert.True(t, ok)
+		deadlines = append(deadlines, time.Until(deadline))
+	}).Return(domain.Article{}, domain.ErrNotFound)
+
+	u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
+	u.GetByID(context.TODO(), 1)
+	u.(interface{ SetContextTimeout(time.Duration) }).SetContextTimeout(time.Minute)
+	u.GetByID(context.TODO(), 1)
+
+	assert.Len(t, deadlines, 2)
+	assert.True(t, deadlines[0] <= time.Second*2)
+	assert.True(t, deadlines[1] > time.Second*2)
+}
+
 func TestStore(t *testing.T) {
 	mockArticleRepo := new(mocks.ArticleRepository)
 	mockArticle := domain.Article{
@@ -122,7 +178,7 @@ func TestStore(t *testing.T) {
 			Return(nil).Once()
 
 		mockAuthorRepo := new(mocks.AuthorRepository)
-		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, time.Second*2)
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
 
 		err := u.Store(context.TODO(), &tempMockArticle)
 
@@ -144,7 +200,7 @@ func TestStore(t *testing.T) {
 		mockAuthorRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).
 			Return(mockAuthor, nil).Once()
 
-		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, time.Second*2)
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
 		err := u.Store(context.TODO(), &mockArticle)
 
 		assert.Error(t, err)
@@ -165,7 +221,7 @@ func TestDelete(t *testing.T) {
 		mockArticleRepo.On("Delete", mock.Anything, mock.AnythingOfType("int64")).Return(nil).Once()
 
 		mockAuthorRepo := new(mocks.AuthorRepository)
-		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, time.Second*2)
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
 
 		err := u.Delete(context.TODO(), mockArticle.ID)
 		assert.NoError(t, err)
@@ -179,7 +235,7 @@ func TestDelete(t *testing.T) {
 			Return(domain.Article{}, nil).Once()
 
 		mockAuthorRepo := new(mocks.AuthorRepository)
-		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, time.Second*2)
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
 
 		err := u.Delete(context.TODO(), mockArticle.ID)
 
@@ -191,7 +247,7 @@ func TestDelete(t *testing.T) {
 		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).
 			Return(domain.Article{}, errors.New("unexpected error")).Once()
 		mockAuthorRepo := new(mocks.AuthorRepository)
-		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, time.Second*2)
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
 
 		err := u.Delete(context.TODO(), mockArticle.ID)
 
@@ -199,6 +255,16 @@ func TestDelete(t *testing.T) {
 		mockArticleRepo.AssertExpectations(t)
 		mockAuthorRepo.AssertExpectations(t)
 	})
+	t.Run("anonymous", func(t *testing.T) {
+		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockArticle, nil).Once()
+		mockAuthorRepo := new(mocks.AuthorRepository)
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewArticlePolicy(), time.Second*2)
+
+		err := u.Delete(context.TODO(), mockArticle.ID)
+
+		assert.Equal(t, domain.ErrForbidden, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
 }
 
 func TestUpdate(t *testing.T) {
@@ -210,13 +276,161 @@ func TestUpdate(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
+		mockArticleRepo.On("GetByID", mock.Anything, mockArticle.ID).Return(mockArticle, nil).Once()
 		mockArticleRepo.On("Update", mock.Anything, &mockArticle).Return(nil).Once()
 
 		mockAuthorRepo := new(mocks.AuthorRepository)
-		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, time.Second*2)
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
+		err := u.Update(context.TODO(), &mockArticle)
+
+		assert.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("keep the creation time", func(t *testing.T) {
+		createdAt := time.Now().Add(-time.Hour)
+		stored := domain.Article{ID: 26, Title: "hello", CreatedAt: createdAt}
+		mockArticleRepo.On("GetByID", mock.Anything, stored.ID).Return(stored, nil).Once()
+		mockArticleRepo.On("GetByTitle", mock.Anything, "world").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+
+		u := usecase.NewArticleUseCase(mockArticleRepo, new(mocks.AuthorRepository), usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
+		ar := domain.Article{ID: 26, Title: "world"}
+		err := u.Update(context.TODO(), &ar)
+
+		assert.NoError(t, err)
+		assert.Equal(t, createdAt, ar.CreatedAt)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("title of another article", func(t *testing.T) {
+		stored := domain.Article{ID: 27, Title: "hello"}
+		mockArticleRepo.On("GetByID", mock.Anything, stored.ID).Return(stored, nil).Once()
+		mockArticleRepo.On("GetByTitle", mock.Anything, "taken").Return(domain.Article{ID: 3, Title: "taken"}, nil).Once()
+
+		u := usecase.NewArticleUseCase(mockArticleRepo, new(mocks.AuthorRepository), usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
+		err := u.Update(context.TODO(), &domain.Article{ID: 27, Title: "taken"})
+
+		assert.Equal(t, domain.ErrAlreadyExist, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("article is not exist", func(t *testing.T) {
+		mockArticleRepo.On("GetByID", mock.Anything, mockArticle.ID).
+			Return(domain.Article{}, domain.ErrNotFound).Once()
+
+		mockAuthorRepo := new(mocks.AuthorRepository)
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
 		err := u.Update(context.TODO(), &mockArticle)
 
+		assert.Equal(t, domain.ErrNotFound, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("article of another author", func(t *testing.T) {
+		stored := domain.Article{ID: 24, Title: "hello", Author: domain.Author{ID: 8}}
+		mockArticleRepo.On("GetByID", mock.Anything, stored.ID).Return(stored, nil).Once()
+
+		mockAuthorRepo := new(mocks.AuthorRepository)
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewArticlePolicy(), time.Second*2)
+		ctx := domain.ContextWithPrincipal(context.TODO(), domain.Principal{
+			Subject: "iman", Author: domain.Author{ID: 7}, Roles: []string{domain.RoleAuthor},
+		})
+		// taking the article over does not help
+		err := u.Update(ctx, &domain.Article{ID: 24, Title: "mine", Author: domain.Author{ID: 7}})
+
+		assert.Equal(t, domain.ErrForbidden, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("give the article away", func(t *testing.T) {
+		stored := domain.Article{ID: 25, Title: "hello", Author: domain.Author{ID: 7}}
+		mockArticleRepo.On("GetByID", mock.Anything, stored.ID).Return(stored, nil).Once()
+
+		mockAuthorRepo := new(mocks.AuthorRepository)
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewArticlePolicy(), time.Second*2)
+		ctx := domain.ContextWithPrincipal(context.TODO(), domain.Principal{
+			Subject: "iman", Author: domain.Author{ID: 7}, Roles: []string{domain.RoleAuthor},
+		})
+		err := u.Update(ctx, &domain.Article{ID: 25, Title: "hello", Author: domain.Author{ID: 8}})
+
+		assert.Equal(t, domain.ErrForbidden, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
+
+func TestSearch(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockResult := []domain.ArticleSearchResult{{
+		Article: domain.Article{ID: 1, Title: "hello", Author: domain.Author{ID: 1}},
+		Score:   1.2,
+		Snippet: "<mark>hello</mark>",
+	}}
+
+	t.Run("success", func(t *testing.T) {
+		mockArticleRepo.On("Search", mock.Anything, "hello", "", int64(10)).
+			Return(mockResult, "next-cursor", nil).Once()
+		mockAuthorRepo := new(mocks.AuthorRepository)
+		mockAuthorRepo.On("GetByIDs", mock.Anything, []int64{1}).
+			Return(map[int64]domain.Author{1: {ID: 1, Name: "Iron Man"}}, nil).Once()
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
+
+		list, nextCursor, err := u.Search(context.TODO(), "hello", "", 0)
+
 		assert.NoError(t, err)
+		assert.Equal(t, "next-cursor", nextCursor)
+		assert.Len(t, list, 1)
+		assert.Equal(t, "Iron Man", list[0].Author.Name)
+		assert.Equal(t, 1.2, list[0].Score)
+		mockArticleRepo.AssertExpectations(t)
+		mockAuthorRepo.AssertExpectations(t)
+	})
+
+	t.Run("empty query", func(t *testing.T) {
+		mockAuthorRepo := new(mocks.AuthorRepository)
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
+
+		_, _, err := u.Search(context.TODO(), "  ", "", 0)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("negative num", func(t *testing.T) {
+		mockAuthorRepo := new(mocks.AuthorRepository)
+		u := usecase.NewArticleUseCase(mockArticleRepo, mockAuthorRepo, usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
+
+		_, _, err := u.Search(context.TODO(), "hello", "", -1)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
 		mockArticleRepo.AssertExpectations(t)
 	})
 }
+
+func TestWithMemoryRepository(t *testing.T) {
+	articleRepo := articleMemory.NewMemoryArticleRepository()
+	authorRepo := authorMemory.NewMemoryAuthorRepository()
+	u := usecase.NewArticleUseCase(articleRepo, authorRepo, usecase.NewUnrestrictedArticlePolicy(), time.Second*2)
+
+	author := domain.Author{Name: "Iron Man"}
+	assert.NoError(t, authorRepo.Store(context.TODO(), &author))
+
+	ar := domain.Article{Title: "hello", Content: "content", Author: domain.Author{ID: author.ID}}
+	assert.NoError(t, u.Store(context.TODO(), &ar))
+
+	duplicated := domain.Article{Title: "hello", Content: "content"}
+	assert.Equal(t, domain.ErrAlreadyExist, u.Store(context.TODO(), &duplicated))
+
+	list, _, _, err := u.Fetch(context.TODO(), domain.ArticleFilter{}, "", 10, "", "")
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, "Iron Man", list[0].Author.Name)
+
+	missing := domain.Article{ID: 99, Title: "missing"}
+	assert.Equal(t, domain.ErrNotFound, u.Update(context.TODO(), &missing))
+	assert.Equal(t, domain.ErrNotFound, u.Delete(context.TODO(), 99))
+
+	assert.NoError(t, u.Delete(context.TODO(), ar.ID))
+	_, err = u.GetByID(context.TODO(), ar.ID)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/logging"
+
+	"github.com/sirupsen/logrus"
+)
+
+type rolePolicy struct{}
+
+// NewArticlePolicy will create the policy granting the actions by the roles of the principal:
+//   - everyone may read the articles, anonymous callers included
+//   - an admin may do anything
+//   - an editor may create and update the articles of every author, and delete its own
+//   - an author may create, update and delete its own articles
+//
+// An anonymous caller may not change anything.
+func NewArticlePolicy() domain.ArticlePolicy {
+	return rolePolicy{}
+}
+
+func (rolePolicy) Authorize(ctx context.Context, action domain.ArticleAction, ar domain.Article) error {
+	if action == domain.ArticleRead {
+		return nil
+	}
+
+	p, ok := domain.PrincipalFromContext(ctx)
+	if ok && allowed(p, action, ar) {
+		return nil
+	}
+	logging.FromContext(ctx).WithFields(logrus.Fields{
+		"action":     action,
+		"article_id": ar.ID,
+		"author_id":  ar.Author.ID,
+	}).Info("article action denied")
+	return domain.ErrForbidden
+}
+
+func allowed(p domain.Principal, action domain.ArticleAction, ar domain.Article) bool {
+	owner := p.Author.ID != 0 && p.Author.ID == ar.Author.ID
+	switch {
+	case p.HasRole(domain.RoleAdmin):
+		return true
+	case p.HasRole(domain.RoleEditor):
+		return action != domain.ArticleDelete || owner
+	case p.HasRole(domain.RoleAuthor):
+		return owner
+	default:
+		return false
+	}
+}
+
+type unrestrictedPolicy struct{}
+
+// NewUnrestrictedArticlePolicy will create the policy allowing every action to everyone, for the trusted callers
+// only, e.g. the commands or a server without authentication
+func NewUnrestrictedArticlePolicy() domain.ArticlePolicy {
+	return unrestrictedPolicy{}
+}
+
+func (unrestrictedPolicy) Authorize(ctx context.Context, action domain.ArticleAction, ar domain.Article) error {
+	return nil
+}
@@ -0,0 +1,74 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/phantomnat/go-clean-architecture/article/usecase"
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArticlePolicy(t *testing.T) {
+	own := domain.Article{ID: 1, Author: domain.Author{ID: 7}}
+	other := domain.Article{ID: 2, Author: domain.Author{ID: 8}}
+
+	principal := func(authorID int64, roles ...string) *domain.Principal {
+		return &domain.Principal{Subject: "someone", Author: domain.Author{ID: authorID}, Roles: roles}
+	}
+	tests := []struct {
+		name      string
+		principal *domain.Principal
+		allowed   map[domain.ArticleAction][]domain.Article
+	}{
+		{"anonymous", nil, map[domain.ArticleAction][]domain.Article{
+			domain.ArticleRead: {own, other},
+		}},
+		{"no role", principal(7), map[domain.ArticleAction][]domain.Article{
+			domain.ArticleRead: {own, other},
+		}},
+		{"author", principal(7, domain.RoleAuthor), map[domain.ArticleAction][]domain.Article{
+			domain.ArticleRead:   {own, other},
+			domain.ArticleCreate: {own},
+			domain.ArticleUpdate: {own},
+			domain.ArticleDelete: {own},
+		}},
+		{"author without author", principal(0, domain.RoleAuthor), map[domain.ArticleAction][]domain.Article{
+			domain.ArticleRead: {own, other},
+		}},
+		{"editor", principal(7, domain.RoleEditor), map[domain.ArticleAction][]domain.Article{
+			domain.ArticleRead:   {own, other},
+			domain.ArticleCreate: {own, other},
+			domain.ArticleUpdate: {own, other},
+			domain.ArticleDelete: {own},
+		}},
+		{"admin", principal(0, domain.RoleAdmin), map[domain.ArticleAction][]domain.Article{
+			domain.ArticleRead:   {own, other},
+			domain.ArticleCreate: {own, other},
+			domain.ArticleUpdate: {own, other},
+			domain.ArticleDelete: {own, other},
+		}},
+	}
+
+	policy := usecase.NewArticlePolicy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			if tt.principal != nil {
+				ctx = domain.ContextWithPrincipal(ctx, *tt.principal)
+			}
+			for _, action := range []domain.ArticleAction{domain.ArticleRead, domain.ArticleCreate, domain.ArticleUpdate, domain.ArticleDelete} {
+				for _, ar := range []domain.Article{own, other} {
+					expected := domain.ErrForbidden
+					for _, allowed := range tt.allowed[action] {
+						if allowed.ID == ar.ID {
+							expected = nil
+						}
+					}
+					assert.Equal(t, expected, policy.Authorize(ctx, action, ar), "%s article %d", action, ar.ID)
+				}
+			}
+		})
+	}
+}
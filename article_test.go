@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/phantomnat/go-clean-architecture/article/delivery/http"
+	"github.com/phantomnat/go-clean-architecture/config"
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportAndImportArticles(t *testing.T) {
+	src := openStorage(config.DatabaseConfig{Driver: config.DriverMemory}, false)
+	au, auu := newUsecases(src, false, config.Default().Timeouts.Usecase)
+	_, _, err := seed(context.TODO(), au, auu, 2, 150)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	n, err := exportArticles(context.TODO(), au, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, 150, n)
+	assert.Equal(t, 150, strings.Count(buf.String(), "\n"))
+
+	dst := openStorage(config.DatabaseConfig{Driver: config.DriverMemory}, false)
+	dau, dauu := newUsecases(dst, false, config.Default().Timeouts.Usecase)
+	for _, name := range []string{"Author 1", "Author 2"} {
+		author := domain.Author{Name: name}
+		require.NoError(t, dauu.Store(context.TODO(), &author))
+	}
+
+	buf.WriteString(`{"title":"","content":"content","author":{"id":1}}` + "\n")
+	buf.WriteString(`{"title":"Sample article 1","content":"content","author":{"id":1}}` + "\n")
+	imported, skipped, err := importArticles(context.TODO(), dau, http.NewArticleValidator(dauu), &buf)
+	require.NoError(t, err)
+	assert.Equal(t, 150, imported)
+	assert.Equal(t, 2, skipped)
+
+	ar, err := dau.GetByTitle(context.TODO(), "Sample article 150")
+	require.NoError(t, err)
+	assert.Equal(t, "Author 2", ar.Author.Name)
+
+	_, _, err = importArticles(context.TODO(), dau, http.NewArticleValidator(dauu), strings.NewReader("{"))
+	assert.Error(t, err)
+}
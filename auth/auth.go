@@ -0,0 +1,155 @@
+// Package auth authenticates the callers of the HTTP API with the JWTs of the Authorization header.
+// The tokens are signed with HS256 or RS256, the authenticated principal is passed along in the request context.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// ErrMissingToken is answered to the mutations without bearer token
+var ErrMissingToken = errors.New("authentication required")
+
+// Config holds the keys verifying the tokens, at least one of them is required
+type Config struct {
+	// Secret is the HS256 shared secret
+	Secret string
+	// PublicKey is the PEM encoded RSA public key of the RS256 tokens
+	PublicKey string
+	// JWKSFile is a local JSON Web Key Set, e.g. the one published by the identity provider
+	JWKSFile string
+	// Issuer and Audience, when set, must be the iss and one of the aud of the tokens
+	Issuer   string
+	Audience string
+}
+
+// Claims are the claims of the tokens, author_id links the subject to its domain.Author
+type Claims struct {
+	jwt.RegisteredClaims
+	AuthorID int64    `json:"author_id,omitempty"`
+	Roles    []string `json:"roles,omitempty"`
+}
+
+// Authenticator verifies the tokens and resolves their principal
+type Authenticator struct {
+	keys     keySet
+	issuer   string
+	audience string
+	authors  domain.AuthorRepository
+	parser   *jwt.Parser
+}
+
+// NewAuthenticator will create the authenticator of the configured keys, the authors of the tokens are
+// looked up in given repository
+func NewAuthenticator(cfg Config, authors domain.AuthorRepository) (*Authenticator, error) {
+	var keys keySet
+	if cfg.Secret != "" {
+		keys = append(keys, key{alg: AlgHS256, value: []byte(cfg.Secret)})
+	}
+	if cfg.PublicKey != "" {
+		pub, err := parseRSAPublicKey([]byte(cfg.PublicKey))
+		if err != nil {
+			return nil, fmt.Errorf("auth: public key: %v", err)
+		}
+		keys = append(keys, key{alg: AlgRS256, value: pub})
+	}
+	if cfg.JWKSFile != "" {
+		jwks, err := readJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("auth: %v", err)
+		}
+		keys = append(keys, jwks...)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("auth: no key configured")
+	}
+
+	return &Authenticator{
+		keys:     keys,
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		authors:  authors,
+		// only the algorithms of the keys are accepted, a token cannot pick "none" or sign HS256 with the public key
+		parser: jwt.NewParser(jwt.WithValidMethods([]string{AlgHS256, AlgRS256})),
+	}, nil
+}
+
+// Authenticate verifies the token and returns its principal.
+// It returns a *TokenError when the token is not valid, other errors come from the author repository.
+func (a *Authenticator) Authenticate(ctx context.Context, token string) (domain.Principal, error) {
+	var claims Claims
+	if _, err := a.parser.ParseWithClaims(token, &claims, a.keys.lookup); err != nil {
+		return domain.Principal{}, &TokenError{Reason: parseReason(err)}
+	}
+	if err := a.verify(&claims); err != nil {
+		return domain.Principal{}, err
+	}
+
+	p := domain.Principal{
+		Subject: claims.Subject,
+		Roles:   claims.Roles,
+	}
+	if claims.AuthorID != 0 {
+		author, err := a.authors.GetByID(ctx, claims.AuthorID)
+		if err == domain.ErrNotFound {
+			return domain.Principal{}, &TokenError{Reason: fmt.Sprintf("unknown author %d", claims.AuthorID)}
+		}
+		if err != nil {
+			return domain.Principal{}, err
+		}
+		p.Author = author
+	}
+	return p, nil
+}
+
+// parseReason tells why the parser rejected the token, without the details of the expiry or the signature
+func parseReason(err error) string {
+	verr, ok := err.(*jwt.ValidationError)
+	if !ok {
+		return err.Error()
+	}
+	switch {
+	case verr.Errors&jwt.ValidationErrorMalformed != 0:
+		return "malformed token"
+	case verr.Errors&jwt.ValidationErrorSignatureInvalid != 0:
+		return "invalid signature"
+	case verr.Errors&jwt.ValidationErrorExpired != 0:
+		return "token is expired"
+	case verr.Errors&(jwt.ValidationErrorNotValidYet|jwt.ValidationErrorIssuedAt) != 0:
+		return "token is not valid yet"
+	default:
+		return verr.Error()
+	}
+}
+
+// verify checks the claims the parser leaves to the caller
+func (a *Authenticator) verify(claims *Claims) error {
+	if claims.Subject == "" {
+		return &TokenError{Reason: "no subject"}
+	}
+	// a token without expiry could never be taken back
+	if claims.ExpiresAt == nil {
+		return &TokenError{Reason: "no expiry"}
+	}
+	if a.issuer != "" && !claims.VerifyIssuer(a.issuer, true) {
+		return &TokenError{Reason: "unexpected issuer"}
+	}
+	if a.audience != "" && !claims.VerifyAudience(a.audience, true) {
+		return &TokenError{Reason: "unexpected audience"}
+	}
+	return nil
+}
+
+// TokenError tells why a token is not valid: malformed, expired, wrongly signed or of an unknown author
+type TokenError struct {
+	Reason string
+}
+
+func (e *TokenError) Error() string {
+	return "invalid token: " + e.Reason
+}
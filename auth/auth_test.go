@@ -0,0 +1,358 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/auth"
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/domain/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+const secret = "0123456789abcdef0123456789abcdef"
+
+var author = domain.Author{ID: 7, Name: "Iman Tumorang"}
+
+func newAuthors() *mocks.AuthorRepository {
+	authors := new(mocks.AuthorRepository)
+	authors.On("GetByID", mock.Anything, author.ID).Return(author, nil)
+	authors.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(domain.Author{}, domain.ErrNotFound)
+	return authors
+}
+
+func newClaims() auth.Claims {
+	return auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "iman",
+			Issuer:    "https://id.example.com",
+			Audience:  jwt.ClaimStrings{"articles"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		AuthorID: author.ID,
+		Roles:    []string{"author"},
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims auth.Claims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+func TestAuthenticateHS256(t *testing.T) {
+	a, err := auth.NewAuthenticator(auth.Config{
+		Secret:   secret,
+		Issuer:   "https://id.example.com",
+		Audience: "articles",
+	}, newAuthors())
+	require.NoError(t, err)
+
+	p, err := a.Authenticate(context.TODO(), sign(t, jwt.SigningMethodHS256, []byte(secret), "", newClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, domain.Principal{Subject: "iman", Author: author, Roles: []string{domain.RoleAuthor}}, p)
+
+	// the kid the identity provider gives to the secret
+	_, err = a.Authenticate(context.TODO(), sign(t, jwt.SigningMethodHS256, []byte(secret), "2024-01", newClaims()))
+	assert.NoError(t, err)
+
+	noAuthor := newClaims()
+	noAuthor.AuthorID = 0
+	p, err = a.Authenticate(context.TODO(), sign(t, jwt.SigningMethodHS256, []byte(secret), "", noAuthor))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), p.Author.ID)
+
+	invalid := map[string]func(c *auth.Claims) (method jwt.SigningMethod, key interface{}){
+		"expired": func(c *auth.Claims) (jwt.SigningMethod, interface{}) {
+			c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+			return jwt.SigningMethodHS256, []byte(secret)
+		},
+		"no expiry": func(c *auth.Claims) (jwt.SigningMethod, interface{}) {
+			c.ExpiresAt = nil
+			return jwt.SigningMethodHS256, []byte(secret)
+		},
+		"no subject": func(c *auth.Claims) (jwt.SigningMethod, interface{}) {
+			c.Subject = ""
+			return jwt.SigningMethodHS256, []byte(secret)
+		},
+		"wrong secret": func(c *auth.Claims) (jwt.SigningMethod, interface{}) {
+			return jwt.SigningMethodHS256, []byte("another secret of 32 bytes long!")
+		},
+		"wrong issuer": func(c *auth.Claims) (jwt.SigningMethod, interface{}) {
+			c.Issuer = "https://evil.example.com"
+			return jwt.SigningMethodHS256, []byte(secret)
+		},
+		"wrong audience": func(c *auth.Claims) (jwt.SigningMethod, interface{}) {
+			c.Audience = jwt.ClaimStrings{"billing"}
+			return jwt.SigningMethodHS256, []byte(secret)
+		},
+		"unknown author": func(c *auth.Claims) (jwt.SigningMethod, interface{}) {
+			c.AuthorID = 42
+			return jwt.SigningMethodHS256, []byte(secret)
+		},
+		"alg none": func(c *auth.Claims) (jwt.SigningMethod, interface{}) {
+			return jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType
+		},
+		"other alg": func(c *auth.Claims) (jwt.SigningMethod, interface{}) {
+			return jwt.SigningMethodHS512, []byte(secret)
+		},
+	}
+	for name, tamper := range invalid {
+		t.Run(name, func(t *testing.T) {
+			claims := newClaims()
+			method, key := tamper(&claims)
+			_, err := a.Authenticate(context.TODO(), sign(t, method, key, "", claims))
+			require.Error(t, err)
+			assert.IsType(t, &auth.TokenError{}, err)
+		})
+	}
+
+	_, err = a.Authenticate(context.TODO(), "not.a.token")
+	assert.IsType(t, &auth.TokenError{}, err)
+}
+
+func TestAuthenticateRS256(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+	pub := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	a, err := auth.NewAuthenticator(auth.Config{PublicKey: string(pub)}, newAuthors())
+	require.NoError(t, err)
+
+	p, err := a.Authenticate(context.TODO(), sign(t, jwt.SigningMethodRS256, priv, "", newClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, author, p.Author)
+
+	t.Run("HS256 signed with the public key", func(t *testing.T) {
+		_, err := a.Authenticate(context.TODO(), sign(t, jwt.SigningMethodHS256, pub, "", newClaims()))
+		assert.IsType(t, &auth.TokenError{}, err)
+	})
+
+	_, err = auth.NewAuthenticator(auth.Config{PublicKey: "not a key"}, newAuthors())
+	assert.EqualError(t, err, "auth: public key: no PEM block found")
+}
+
+func TestAuthenticateJWKS(t *testing.T) {
+	first, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	second, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	rsaKey := func(kid string, k *rsa.PrivateKey) map[string]string {
+		return map[string]string{
+			"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256",
+			"n": b64(k.N.Bytes()), "e": b64(big.NewInt(int64(k.E)).Bytes()),
+		}
+	}
+	byt, err := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": "AA", "y": "AA"},
+		rsaKey("first", first),
+		rsaKey("second", second),
+		{"kty": "oct", "kid": "shared", "k": b64([]byte(secret))},
+	}})
+	require.NoError(t, err)
+	f, err := ioutil.TempFile("", "jwks")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.Write(byt)
+	require.NoError(t, err)
+	f.Close()
+
+	a, err := auth.NewAuthenticator(auth.Config{JWKSFile: f.Name()}, newAuthors())
+	require.NoError(t, err)
+
+	_, err = a.Authenticate(context.TODO(), sign(t, jwt.SigningMethodRS256, second, "second", newClaims()))
+	assert.NoError(t, err)
+	_, err = a.Authenticate(context.TODO(), sign(t, jwt.SigningMethodHS256, []byte(secret), "shared", newClaims()))
+	assert.NoError(t, err)
+
+	_, err = a.Authenticate(context.TODO(), sign(t, jwt.SigningMethodRS256, second, "first", newClaims()))
+	assert.IsType(t, &auth.TokenError{}, err)
+	_, err = a.Authenticate(context.TODO(), sign(t, jwt.SigningMethodRS256, second, "third", newClaims()))
+	assert.EqualError(t, err, `invalid token: unknown RS256 key "third"`)
+
+	// the keys of the config verify the tokens of the kids the JWKS does not have
+	other := "another secret of 32 bytes long!"
+	a, err = auth.NewAuthenticator(auth.Config{JWKSFile: f.Name(), Secret: other}, newAuthors())
+	require.NoError(t, err)
+	_, err = a.Authenticate(context.TODO(), sign(t, jwt.SigningMethodHS256, []byte(other), "idp", newClaims()))
+	assert.NoError(t, err)
+	_, err = a.Authenticate(context.TODO(), sign(t, jwt.SigningMethodHS256, []byte(secret), "shared", newClaims()))
+	assert.NoError(t, err)
+	_, err = a.Authenticate(context.TODO(), sign(t, jwt.SigningMethodHS256, []byte(secret), "idp", newClaims()))
+	assert.EqualError(t, err, "invalid token: invalid signature")
+
+	_, err = auth.NewAuthenticator(auth.Config{JWKSFile: f.Name() + ".missing"}, newAuthors())
+	assert.Error(t, err)
+	_, err = auth.NewAuthenticator(auth.Config{}, newAuthors())
+	assert.EqualError(t, err, "auth: no key configured")
+
+	byt, err = json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{"kty": "oct", "kid": "short", "k": b64([]byte("short"))},
+	}})
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(f.Name(), byt, 0600))
+	_, err = auth.NewAuthenticator(auth.Config{JWKSFile: f.Name()}, newAuthors())
+	assert.EqualError(t, err, "auth: "+f.Name()+": key 0: symmetric key must be at least 32 bytes, got 5")
+
+}
+
+func TestMiddleware(t *testing.T) {
+	a, err := auth.NewAuthenticator(auth.Config{Secret: secret}, newAuthors())
+	require.NoError(t, err)
+
+	e := gin.New()
+	e.Use(auth.Middleware(a))
+	handler := func(c *gin.Context) {
+		p, ok := domain.PrincipalFromContext(c.Request.Context())
+		if !ok {
+			c.String(http.StatusOK, "anonymous")
+			return
+		}
+		c.String(http.StatusOK, p.Author.Name)
+	}
+	e.GET("/articles", handler)
+	e.POST("/articles", handler)
+
+	valid := sign(t, jwt.SigningMethodHS256, []byte(secret), "", newClaims())
+	expired := newClaims()
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+	tests := []struct {
+		name   string
+		method string
+		header string
+		status int
+		body   string
+	}{
+		{"anonymous read", http.MethodGet, "", http.StatusOK, "anonymous"},
+		{"authenticated read", http.MethodGet, "Bearer " + valid, http.StatusOK, author.Name},
+		{"anonymous mutation", http.MethodPost, "", http.StatusUnauthorized, `{"message":"authentication required"}`},
+		{"other scheme", http.MethodPost, "Basic aW1hbjpzM2NyZXQ=", http.StatusUnauthorized, `{"message":"authentication required"}`},
+		{"authenticated mutation", http.MethodPost, "bearer " + valid, http.StatusOK, author.Name},
+		{"expired token", http.MethodGet, "Bearer " + sign(t, jwt.SigningMethodHS256, []byte(secret), "", expired),
+			http.StatusUnauthorized, `{"message":"invalid token: token is expired"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/articles", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, tt.body, rec.Body.String())
+			if tt.status == http.StatusUnauthorized {
+				assert.Equal(t, `Bearer realm="api"`, rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	var principal *domain.Principal
+	e := gin.New()
+	e.Use(func(c *gin.Context) {
+		if principal != nil {
+			c.Request = c.Request.WithContext(domain.ContextWithPrincipal(c.Request.Context(), *principal))
+		}
+	})
+	e.POST("/articles", auth.RequireScope(domain.ScopeArticlesWrite), func(c *gin.Context) {
+		c.String(http.StatusOK, "done")
+	})
+
+	tests := []struct {
+		name      string
+		principal *domain.Principal
+		status    int
+		body      string
+	}{
+		{"anonymous", nil, http.StatusOK, "done"},
+		{"token", &domain.Principal{Subject: "42", Roles: []string{domain.RoleAuthor}}, http.StatusOK, "done"},
+		{"api key with the scope", &domain.Principal{Subject: "apikey:gca_1234", APIKeyID: 1,
+			Scopes: []domain.APIKeyScope{domain.ScopeArticlesWrite}}, http.StatusOK, "done"},
+		{"api key without the scope", &domain.Principal{Subject: "apikey:gca_1234", APIKeyID: 1,
+			Scopes: []domain.APIKeyScope{domain.ScopeArticlesRead}}, http.StatusForbidden, `{"message":"you are not allowed to perform this action"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal = tt.principal
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/articles", nil))
+
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, tt.body, rec.Body.String())
+		})
+	}
+}
+
+func TestRequireCredentials(t *testing.T) {
+	e := gin.New()
+	e.Use(func(c *gin.Context) {
+		if c.GetHeader("X-API-Key") != "" {
+			p := domain.Principal{Subject: "apikey:gca_1234", APIKeyID: 1}
+			c.Request = c.Request.WithContext(domain.ContextWithPrincipal(c.Request.Context(), p))
+		}
+	})
+	e.Use(auth.RequireCredentials())
+	handler := func(c *gin.Context) {
+		c.String(http.StatusOK, "done")
+	}
+	e.GET("/articles", handler)
+	e.POST("/articles", handler)
+
+	tests := []struct {
+		name   string
+		method string
+		key    string
+		status int
+		body   string
+	}{
+		{"anonymous read", http.MethodGet, "", http.StatusOK, "done"},
+		{"anonymous mutation", http.MethodPost, "", http.StatusUnauthorized, `{"message":"authentication required"}`},
+		{"authenticated mutation", http.MethodPost, "gca_1234", http.StatusOK, "done"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/articles", nil)
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, tt.body, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// responseError has the shape of the ResponseError of the handlers
+type responseError struct {
+	Message string `json:"message"`
+}
+
+// Middleware authenticates the bearer token of the requests and puts the principal in their context.
+// The reads may be anonymous, a mutation without token is answered 401, as is any request with an invalid token.
+// The requests already authenticated, e.g. by an API key, are let through without token.
+func Middleware(a *Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c.Request)
+		if !ok {
+			// authenticated by an API key
+			if _, ok := domain.PrincipalFromContext(c.Request.Context()); ok {
+				c.Next()
+				return
+			}
+			if isMutation(c.Request.Method) {
+				unauthorized(c, ErrMissingToken)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		p, err := a.Authenticate(ctx, token)
+		if _, ok := err.(*TokenError); ok {
+			logging.FromContext(ctx).Info(err)
+			unauthorized(c, err)
+			return
+		}
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, responseError{Message: domain.ErrInternalServer.Error()})
+			return
+		}
+
+		ctx = domain.ContextWithPrincipal(ctx, p)
+		ctx = logging.NewContext(ctx, logging.FromContext(ctx).WithField("subject", p.Subject))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// RequireCredentials answers 401 to the anonymous mutations, it replaces the Middleware when the callers are only
+// authenticated by other means, e.g. an API key
+func RequireCredentials() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := domain.PrincipalFromContext(c.Request.Context()); !ok && isMutation(c.Request.Method) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, responseError{Message: ErrMissingToken.Error()})
+		}
+	}
+}
+
+// RequireScope answers 403 to the callers whose API key was not granted the scope of the route
+func RequireScope(scope domain.APIKeyScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if p, ok := domain.PrincipalFromContext(c.Request.Context()); ok && !p.Allows(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, responseError{Message: domain.ErrForbidden.Error()})
+		}
+	}
+}
+
+// bearerToken returns the token of the Authorization header, ok is false when there is none
+func bearerToken(r *http.Request) (token string, ok bool) {
+	h := r.Header.Get("Authorization")
+	// the scheme is case-insensitive, RFC 7235
+	if len(h) < 7 || !strings.EqualFold(h[:7], "Bearer ") {
+		return "", false
+	}
+	token = strings.TrimSpace(h[7:])
+	return token, token != ""
+}
+
+// isMutation tells whether the method may change the resources
+func isMutation(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+func unauthorized(c *gin.Context, err error) {
+	c.Header("WWW-Authenticate", `Bearer realm="api"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, responseError{Message: err.Error()})
+}
@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// MinSecretLength is the size of the HS256 hash, a shorter secret is easier to brute force than the hash
+const MinSecretLength = 32
+
+// Signing algorithms of the tokens
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+// key is a verification key, id is the kid of the JWKS keys and empty for the keys of the config
+type key struct {
+	id    string
+	alg   string
+	value interface{}
+}
+
+// keySet holds the keys the tokens may be signed with
+type keySet []key
+
+// lookup returns the key of the token: the key of its kid, otherwise the first key of its algorithm without id.
+// The keys of the config have no id, they verify the tokens of a kid the JWKS does not know, e.g. the kid the
+// identity provider gives to the shared secret.
+func (s keySet) lookup(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	var fallback interface{}
+	for _, k := range s {
+		if k.alg != token.Method.Alg() {
+			continue
+		}
+		if kid == "" || k.id == kid {
+			return k.value, nil
+		}
+		if k.id == "" && fallback == nil {
+			fallback = k.value
+		}
+	}
+	if fallback != nil {
+		return fallback, nil
+	}
+	if kid != "" {
+		return nil, fmt.Errorf("unknown %s key %q", token.Method.Alg(), kid)
+	}
+	return nil, fmt.Errorf("no %s key", token.Method.Alg())
+}
+
+// parseRSAPublicKey reads the PEM encoded RSA public key, in the PKIX or PKCS #1 form, or the one of a certificate
+func parseRSAPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var pub interface{}
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		pub = cert.PublicKey
+	default:
+		var err error
+		pub, err = x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rsaPub, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("not an RSA public key: %T", pub)
+	}
+	return rsaPub, nil
+}
+
+// jwk is a JSON Web Key of RFC 7517, only the fields of the RSA and symmetric keys are read
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+// readJWKS reads the RSA and symmetric signing keys of the JWKS file, the keys of other types or uses are skipped
+func readJWKS(file string) (keySet, error) {
+	byt, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(byt, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+
+	var keys keySet
+	for i, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		parsed, err := k.parse()
+		if err != nil {
+			return nil, fmt.Errorf("%s: key %d: %v", file, i, err)
+		}
+		if parsed.value == nil {
+			continue
+		}
+		keys = append(keys, parsed)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%s: no RSA or symmetric signing key", file)
+	}
+	return keys, nil
+}
+
+// parse returns the key, its value is nil when the key cannot verify HS256 or RS256 tokens
+func (k jwk) parse() (key, error) {
+	switch {
+	case k.Kty == "RSA" && (k.Alg == "" || k.Alg == AlgRS256):
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return key{}, fmt.Errorf("invalid modulus: %v", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return key{}, fmt.Errorf("invalid exponent: %v", err)
+		}
+		exp := new(big.Int).SetBytes(e)
+		if len(n) == 0 || !exp.IsInt64() || exp.Int64() < 3 || exp.Int64() > 1<<31-1 {
+			return key{}, errors.New("invalid RSA public key")
+		}
+		pub := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}
+		return key{id: k.Kid, alg: AlgRS256, value: pub}, nil
+	case k.Kty == "oct" && (k.Alg == "" || k.Alg == AlgHS256):
+		secret, err := base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil {
+			return key{}, fmt.Errorf("invalid symmetric key: %v", err)
+		}
+		if len(secret) < MinSecretLength {
+			return key{}, fmt.Errorf("symmetric key must be at least %d bytes, got %d", MinSecretLength, len(secret))
+		}
+		return key{id: k.Kid, alg: AlgHS256, value: secret}, nil
+	default:
+		return key{}, nil
+	}
+}
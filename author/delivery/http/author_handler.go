@@ -0,0 +1,178 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/phantomnat/go-clean-architecture/auth"
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/logging"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// ResponseError represents the response error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// AuthorHandler represents the http handler for author
+type AuthorHandler struct {
+	AuthorUsecase domain.AuthorUsecase
+}
+
+func NewAuthorHttpHandler(e *gin.Engine, au domain.AuthorUsecase) {
+	handler := &AuthorHandler{
+		AuthorUsecase: au,
+	}
+
+	manage := auth.RequireScope(domain.ScopeAuthorsManage)
+	e.GET("/authors", handler.FetchAuthor)
+	e.GET("/authors/:id", handler.GetByID)
+	e.POST("/authors", manage, handler.Store)
+	e.PUT("/authors/:id", manage, handler.Update)
+	e.DELETE("/authors/:id", manage, handler.Delete)
+}
+
+// FetchAuthor will fetch the author based on given params
+func (a *AuthorHandler) FetchAuthor(c *gin.Context) {
+	n := c.Query("num")
+	num, _ := strconv.Atoi(n)
+
+	cursor := c.Query("cursor")
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	listAu, nextCursor, err := a.AuthorUsecase.Fetch(ctx, cursor, int64(num))
+	if err != nil {
+		c.AbortWithStatusJSON(getStatusCode(ctx, err), ResponseError{Message: err.Error()})
+		return
+	}
+
+	c.Header("X-Cursor", nextCursor)
+	c.JSON(http.StatusOK, listAu)
+}
+
+// GetByID returns author by given id
+func (a *AuthorHandler) GetByID(c *gin.Context) {
+	i, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, ResponseError{Message: domain.ErrNotFound.Error()})
+		return
+	}
+
+	id := int64(i)
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	au, err := a.AuthorUsecase.GetByID(ctx, id)
+	if err != nil {
+		c.AbortWithStatusJSON(getStatusCode(ctx, err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, au)
+}
+
+// Store will store the author by given request body
+func (a *AuthorHandler) Store(c *gin.Context) {
+	var au domain.Author
+	if err := c.ShouldBindJSON(&au); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+		return
+	}
+
+	if err := isRequestValid(&au); err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, ResponseError{Message: err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	err := a.AuthorUsecase.Store(ctx, &au)
+	if err != nil {
+		c.AbortWithStatusJSON(getStatusCode(ctx, err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, au)
+}
+
+// Update will update the author by given id and request body
+func (a *AuthorHandler) Update(c *gin.Context) {
+	i, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, ResponseError{Message: domain.ErrNotFound.Error()})
+		return
+	}
+
+	var au domain.Author
+	if err := c.ShouldBindJSON(&au); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+		return
+	}
+	au.ID = int64(i)
+
+	if err := isRequestValid(&au); err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, ResponseError{Message: err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	err = a.AuthorUsecase.Update(ctx, &au)
+	if err != nil {
+		c.AbortWithStatusJSON(getStatusCode(ctx, err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, au)
+}
+
+// Delete will delete the author by given id
+func (a *AuthorHandler) Delete(c *gin.Context) {
+	i, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, ResponseError{Message: domain.ErrNotFound.Error()})
+		return
+	}
+
+	id := int64(i)
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	err = a.AuthorUsecase.Delete(ctx, id)
+	if err != nil {
+		c.AbortWithStatusJSON(getStatusCode(ctx, err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func isRequestValid(au *domain.Author) error {
+	validate := validator.New()
+	return validate.Struct(au)
+}
+
+func getStatusCode(ctx context.Context, err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	logging.FromContext(ctx).Error(err)
+	switch err {
+	case domain.ErrInternalServer:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrAlreadyExist, domain.ErrAuthorHasArticles:
+		return http.StatusConflict
+	case domain.ErrBadParamInput:
+		return http.StatusBadRequest
+	case domain.ErrForbidden:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,104 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authorHttp "github.com/phantomnat/go-clean-architecture/author/delivery/http"
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/domain/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestFetch(t *testing.T) {
+	mockUCase := new(mocks.AuthorUsecase)
+	mockListAuthor := []domain.Author{{ID: 1, Name: "Iron Man"}}
+	mockUCase.On("Fetch", mock.Anything, "", int64(1)).Return(mockListAuthor, "next-cursor", nil).Once()
+
+	e := gin.New()
+	authorHttp.NewAuthorHttpHandler(e, mockUCase)
+
+	req := httptest.NewRequest(http.MethodGet, "/authors?num=1", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "next-cursor", rec.Header().Get("X-Cursor"))
+	mockUCase.AssertExpectations(t)
+}
+
+func TestStore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.AuthorUsecase)
+		mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Author")).Return(nil).Once()
+
+		e := gin.New()
+		authorHttp.NewAuthorHttpHandler(e, mockUCase)
+
+		body, err := json.Marshal(domain.Author{Name: "Iron Man"})
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/authors", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		mockUCase := new(mocks.AuthorUsecase)
+
+		e := gin.New()
+		authorHttp.NewAuthorHttpHandler(e, mockUCase)
+
+		req := httptest.NewRequest(http.MethodPost, "/authors", bytes.NewReader([]byte("{}")))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	mockUCase := new(mocks.AuthorUsecase)
+	mockUCase.On("Delete", mock.Anything, int64(12)).Return(domain.ErrNotFound).Once()
+
+	e := gin.New()
+	authorHttp.NewAuthorHttpHandler(e, mockUCase)
+
+	req := httptest.NewRequest(http.MethodDelete, "/authors/12", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestDeleteWithArticles(t *testing.T) {
+	mockUCase := new(mocks.AuthorUsecase)
+	mockUCase.On("Delete", mock.Anything, int64(12)).Return(domain.ErrAuthorHasArticles).Once()
+
+	e := gin.New()
+	authorHttp.NewAuthorHttpHandler(e, mockUCase)
+
+	req := httptest.NewRequest(http.MethodDelete, "/authors/12", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.JSONEq(t, `{"message":"the author still has articles"}`, rec.Body.String())
+	mockUCase.AssertExpectations(t)
+}
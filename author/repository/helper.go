@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"encoding/base64"
+	"strconv"
+)
+
+// DecodeCursor will decode cursor from user for mysql
+func DecodeCursor(encodedID string) (int64, error) {
+	byt, err := base64.StdEncoding.DecodeString(encodedID)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(string(byt), 10, 64)
+}
+
+// EncodeCursor will encode cursor from mysql to user
+func EncodeCursor(id int64) string {
+	idString := strconv.FormatInt(id, 10)
+
+	return base64.StdEncoding.EncodeToString([]byte(idString))
+}
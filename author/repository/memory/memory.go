@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/phantomnat/go-clean-architecture/author/repository"
+	"github.com/phantomnat/go-clean-architecture/domain"
+)
+
+type memoryAuthorRepo struct {
+	mu      sync.RWMutex
+	authors map[int64]domain.Author
+	lastID  int64
+}
+
+// NewMemoryAuthorRepository will create an object that represent the author.Repository interface.
+// The authors are kept in memory and are lost when the process exits.
+func NewMemoryAuthorRepository() domain.AuthorRepository {
+	return &memoryAuthorRepo{
+		authors: make(map[int64]domain.Author),
+	}
+}
+
+func (m *memoryAuthorRepo) Fetch(ctx context.Context, cursor string, num int64) (res []domain.Author, nextCursor string, err error) {
+	if num <= 0 {
+		return make([]domain.Author, 0), "", nil
+	}
+
+	decodedCursor, err := repository.DecodeCursor(cursor)
+	if err != nil && cursor != "" {
+		return nil, "", domain.ErrBadParamInput
+	}
+
+	m.mu.RLock()
+	res = make([]domain.Author, 0)
+	for _, au := range m.authors {
+		if au.ID > decodedCursor {
+			res = append(res, au)
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID < res[j].ID
+	})
+	if int64(len(res)) > num {
+		res = res[:num]
+	}
+
+	if len(res) == int(num) {
+		nextCursor = repository.EncodeCursor(res[len(res)-1].ID)
+	}
+
+	return res, nextCursor, nil
+}
+
+func (m *memoryAuthorRepo) GetByID(ctx context.Context, id int64) (domain.Author, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	au, ok := m.authors[id]
+	if !ok {
+		return domain.Author{}, domain.ErrNotFound
+	}
+	return au, nil
+}
+
+func (m *memoryAuthorRepo) GetByIDs(ctx context.Context, ids []int64) (map[int64]domain.Author, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	res := make(map[int64]domain.Author, len(ids))
+	for _, id := range ids {
+		if au, ok := m.authors[id]; ok {
+			res[id] = au
+		}
+	}
+	return res, nil
+}
+
+func (m *memoryAuthorRepo) Store(ctx context.Context, au *domain.Author) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastID++
+	au.ID = m.lastID
+	m.authors[au.ID] = *au
+	return nil
+}
+
+func (m *memoryAuthorRepo) Update(ctx context.Context, au *domain.Author) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existed, ok := m.authors[au.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+
+	updated := *au
+	updated.CreatedAt = existed.CreatedAt
+	m.authors[au.ID] = updated
+	return nil
+}
+
+func (m *memoryAuthorRepo) Delete(ctx context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.authors[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(m.authors, id)
+	return nil
+}
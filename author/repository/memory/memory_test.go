@@ -0,0 +1,49 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/phantomnat/go-clean-architecture/author/repository/memory"
+	"github.com/phantomnat/go-clean-architecture/author/repository/repositorytest"
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorRepository(t *testing.T) {
+	a := memory.NewMemoryAuthorRepository()
+	for _, name := range []string{"Iron Man", "Thor", "Hulk"} {
+		au := domain.Author{Name: name}
+		require.NoError(t, a.Store(context.TODO(), &au))
+	}
+
+	list, nextCursor, err := a.Fetch(context.TODO(), "", 2)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+	list, _, err = a.Fetch(context.TODO(), nextCursor, 2)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "Hulk", list[0].Name)
+
+	res, err := a.GetByIDs(context.TODO(), []int64{1, 3, 4})
+	require.NoError(t, err)
+	assert.Len(t, res, 2)
+
+	au := domain.Author{ID: 2, Name: "Loki"}
+	require.NoError(t, a.Update(context.TODO(), &au))
+	got, err := a.GetByID(context.TODO(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, "Loki", got.Name)
+
+	require.NoError(t, a.Delete(context.TODO(), 2))
+	_, err = a.GetByID(context.TODO(), 2)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestContract(t *testing.T) {
+	repositorytest.RunAuthorRepositoryTests(t, func(t *testing.T) domain.AuthorRepository {
+		return memory.NewMemoryAuthorRepository()
+	})
+}
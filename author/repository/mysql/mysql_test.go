@@ -2,13 +2,20 @@ package mysql_test
 
 import (
 	"context"
+	"database/sql"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/phantomnat/go-clean-architecture/author/repository"
 	"github.com/phantomnat/go-clean-architecture/author/repository/mysql"
+	"github.com/phantomnat/go-clean-architecture/author/repository/repositorytest"
+	"github.com/phantomnat/go-clean-architecture/domain"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetByID(t *testing.T) {
@@ -22,7 +29,7 @@ func TestGetByID(t *testing.T) {
 
 	query := "SELECT id, name, created_at, updated_at FROM author WHERE id=\\?"
 
-	prep := mock.ExpectPrepare(query)
+	prep := mock.ExpectPrepare(query).WillBeClosed()
 	userID := int64(1)
 	prep.ExpectQuery().WithArgs(userID).WillReturnRows(rows)
 
@@ -32,4 +39,158 @@ func TestGetByID(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, anArticle)
 	assert.Equal(t, anArticle.ID, userID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetByIDNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%v' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "name", "updated_at", "created_at"})
+
+	query := "SELECT id, name, created_at, updated_at FROM author WHERE id=\\?"
+
+	prep := mock.ExpectPrepare(query).WillBeClosed()
+	userID := int64(1)
+	prep.ExpectQuery().WithArgs(userID).WillReturnRows(rows)
+
+	a := mysql.NewMysqlAuthorRepository(db)
+
+	_, err = a.GetByID(context.TODO(), userID)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestFetch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%v' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).
+		AddRow(1, "Iron Man", time.Now(), time.Now()).
+		AddRow(2, "Captain America", time.Now(), time.Now())
+
+	query := "SELECT id, name, created_at, updated_at FROM author WHERE id > \\? ORDER BY id LIMIT \\?"
+
+	mock.ExpectQuery(query).WithArgs(int64(0), int64(2)).WillReturnRows(rows)
+	a := mysql.NewMysqlAuthorRepository(db)
+
+	list, nextCursor, err := a.Fetch(context.TODO(), "", int64(2))
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, repository.EncodeCursor(2), nextCursor)
+}
+
+func TestStore(t *testing.T) {
+	now := time.Now()
+	au := &domain.Author{
+		Name:      "Iron Man",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%v' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "INSERT author SET name=\\?, created_at=\\?, updated_at=\\?"
+	prep := mock.ExpectPrepare(query).WillBeClosed()
+	prep.ExpectExec().WithArgs(au.Name, au.CreatedAt, au.UpdatedAt).WillReturnResult(sqlmock.NewResult(12, 1))
+
+	a := mysql.NewMysqlAuthorRepository(db)
+
+	err = a.Store(context.TODO(), au)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), au.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdate(t *testing.T) {
+	au := &domain.Author{
+		ID:        12,
+		Name:      "Iron Man",
+		UpdatedAt: time.Now(),
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%v' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "UPDATE author SET name=\\?, updated_at=\\? WHERE id=\\?"
+	prep := mock.ExpectPrepare(query).WillBeClosed()
+	prep.ExpectExec().WithArgs(au.Name, au.UpdatedAt, au.ID).WillReturnResult(sqlmock.NewResult(12, 1))
+
+	a := mysql.NewMysqlAuthorRepository(db)
+
+	err = a.Update(context.TODO(), au)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%v' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "DELETE FROM author WHERE id=\\?"
+	prep := mock.ExpectPrepare(query).WillBeClosed()
+	prep.ExpectExec().WithArgs(12).WillReturnResult(sqlmock.NewResult(12, 1))
+
+	a := mysql.NewMysqlAuthorRepository(db)
+
+	err = a.Delete(context.TODO(), int64(12))
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetByIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%v' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).
+		AddRow(1, "Iron Man", time.Now(), time.Now()).
+		AddRow(3, "Thor", time.Now(), time.Now())
+
+	query := "SELECT id, name, created_at, updated_at FROM author WHERE id IN \\(\\?,\\?,\\?\\)"
+
+	mock.ExpectQuery(query).WithArgs(int64(1), int64(2), int64(3)).WillReturnRows(rows)
+	a := mysql.NewMysqlAuthorRepository(db)
+
+	res, err := a.GetByIDs(context.TODO(), []int64{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, "Thor", res[3].Name)
+	_, ok := res[2]
+	assert.False(t, ok)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestContract runs against a real MySQL server, e.g.
+// MYSQL_TEST_DSN="root:123456@tcp(localhost:3306)/article_test?parseTime=1&clientFoundRows=true"
+// The database must be migrated with `migrate up`, the author table is emptied before every test case.
+func TestContract(t *testing.T) {
+	dsn := os.Getenv("MYSQL_TEST_DSN")
+	if dsn == "" {
+		t.Skip("MYSQL_TEST_DSN is not set")
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	require.NoError(t, err)
+	defer db.Close()
+
+	repositorytest.RunAuthorRepositoryTests(t, func(t *testing.T) domain.AuthorRepository {
+		_, err := db.Exec("TRUNCATE TABLE author")
+		require.NoError(t, err)
+		return mysql.NewMysqlAuthorRepository(db)
+	})
 }
@@ -0,0 +1,164 @@
+// Package repositorytest provides the conformance suite of domain.AuthorRepository,
+// every storage backend runs it from its own tests to be verified the same way.
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// maxPages stops a pagination walk that never ends
+const maxPages = 100
+
+// AuthorRepositoryFactory returns an empty repository, it is called once for every test case
+type AuthorRepositoryFactory func(t *testing.T) domain.AuthorRepository
+
+// RunAuthorRepositoryTests checks the contract shared by every domain.AuthorRepository implementation
+func RunAuthorRepositoryTests(t *testing.T, newRepo AuthorRepositoryFactory) {
+	t.Run("StoreAndGet", func(t *testing.T) { testStoreAndGet(t, newRepo(t)) })
+	t.Run("GetByIDs", func(t *testing.T) { testGetByIDs(t, newRepo(t)) })
+	t.Run("GetNotFound", func(t *testing.T) { testGetNotFound(t, newRepo(t)) })
+	t.Run("Update", func(t *testing.T) { testUpdate(t, newRepo(t)) })
+	t.Run("Delete", func(t *testing.T) { testDelete(t, newRepo(t)) })
+	t.Run("Paginate", func(t *testing.T) { testPaginate(t, newRepo(t)) })
+	t.Run("NonPositiveNum", func(t *testing.T) { testNonPositiveNum(t, newRepo(t)) })
+}
+
+// baseTime has no sub-second part, the DATETIME columns do not keep it
+var baseTime = time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+
+func store(t *testing.T, repo domain.AuthorRepository, n int) []domain.Author {
+	list := make([]domain.Author, 0, n)
+	for i := 0; i < n; i++ {
+		au := domain.Author{
+			Name:      fmt.Sprintf("author %d", i),
+			CreatedAt: baseTime,
+			UpdatedAt: baseTime,
+		}
+		require.NoError(t, repo.Store(context.TODO(), &au))
+		require.NotZero(t, au.ID, "Store must assign the id of the author")
+		list = append(list, au)
+	}
+	return list
+}
+
+func assertAuthor(t *testing.T, expected, actual domain.Author) {
+	assert.Equal(t, expected.ID, actual.ID)
+	assert.Equal(t, expected.Name, actual.Name)
+	assert.True(t, expected.CreatedAt.Equal(actual.CreatedAt), "created_at: expected %s, actual %s", expected.CreatedAt, actual.CreatedAt)
+	assert.True(t, expected.UpdatedAt.Equal(actual.UpdatedAt), "updated_at: expected %s, actual %s", expected.UpdatedAt, actual.UpdatedAt)
+}
+
+func testStoreAndGet(t *testing.T, repo domain.AuthorRepository) {
+	list := store(t, repo, 2)
+	assert.NotEqual(t, list[0].ID, list[1].ID)
+
+	for _, au := range list {
+		res, err := repo.GetByID(context.TODO(), au.ID)
+		require.NoError(t, err)
+		assertAuthor(t, au, res)
+	}
+}
+
+func testGetByIDs(t *testing.T, repo domain.AuthorRepository) {
+	list := store(t, repo, 3)
+
+	res, err := repo.GetByIDs(context.TODO(), []int64{list[0].ID, list[2].ID, list[2].ID + 1})
+	require.NoError(t, err)
+	// the missing ids are left out of the result
+	require.Len(t, res, 2)
+	assertAuthor(t, list[0], res[list[0].ID])
+	assertAuthor(t, list[2], res[list[2].ID])
+
+	res, err = repo.GetByIDs(context.TODO(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, res)
+}
+
+func testGetNotFound(t *testing.T, repo domain.AuthorRepository) {
+	list := store(t, repo, 1)
+
+	_, err := repo.GetByID(context.TODO(), list[0].ID+1)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func testUpdate(t *testing.T, repo domain.AuthorRepository) {
+	list := store(t, repo, 2)
+
+	au := list[0]
+	au.Name = "updated name"
+	au.UpdatedAt = baseTime.Add(time.Hour)
+	require.NoError(t, repo.Update(context.TODO(), &au))
+
+	res, err := repo.GetByID(context.TODO(), au.ID)
+	require.NoError(t, err)
+	assertAuthor(t, au, res)
+
+	// the other author is left untouched
+	res, err = repo.GetByID(context.TODO(), list[1].ID)
+	require.NoError(t, err)
+	assertAuthor(t, list[1], res)
+
+	missing := domain.Author{ID: list[1].ID + 1, Name: "missing", UpdatedAt: baseTime}
+	assert.Equal(t, domain.ErrNotFound, repo.Update(context.TODO(), &missing))
+}
+
+func testDelete(t *testing.T, repo domain.AuthorRepository) {
+	list := store(t, repo, 2)
+
+	require.NoError(t, repo.Delete(context.TODO(), list[0].ID))
+	_, err := repo.GetByID(context.TODO(), list[0].ID)
+	assert.Equal(t, domain.ErrNotFound, err)
+	assert.Equal(t, domain.ErrNotFound, repo.Delete(context.TODO(), list[0].ID))
+
+	_, err = repo.GetByID(context.TODO(), list[1].ID)
+	assert.NoError(t, err)
+}
+
+// testPaginate walks the whole list, every author must be returned exactly once and ordered by id
+func testPaginate(t *testing.T, repo domain.AuthorRepository) {
+	list := store(t, repo, 7)
+
+	expected := make([]int64, 0, len(list))
+	for _, au := range list {
+		expected = append(expected, au.ID)
+	}
+
+	const num = 3
+	actual := make([]int64, 0, len(list))
+	cursor := ""
+	for i := 0; ; i++ {
+		require.True(t, i < maxPages, "the walk does not end")
+
+		res, nextCursor, err := repo.Fetch(context.TODO(), cursor, num)
+		require.NoError(t, err)
+		require.True(t, len(res) <= num, "the page must hold at most %d authors", num)
+		for _, au := range res {
+			actual = append(actual, au.ID)
+		}
+		if nextCursor == "" {
+			break
+		}
+		cursor = nextCursor
+	}
+	assert.Equal(t, expected, actual, "the walk must return every author once, ordered by id")
+}
+
+// testNonPositiveNum asks for pages of no author, they must be empty whatever is stored
+func testNonPositiveNum(t *testing.T, repo domain.AuthorRepository) {
+	store(t, repo, 2)
+
+	for _, num := range []int64{0, -1} {
+		res, nextCursor, err := repo.Fetch(context.TODO(), "", num)
+		require.NoError(t, err)
+		assert.Empty(t, res, "num %d", num)
+		assert.Empty(t, nextCursor)
+	}
+}
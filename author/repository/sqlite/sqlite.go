@@ -0,0 +1,212 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/phantomnat/go-clean-architecture/author/repository"
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/logging"
+)
+
+type sqliteAuthorRepo struct {
+	DB *sql.DB
+}
+
+// NewSqliteAuthorRepository will create an object that represent the author.Repository interface
+func NewSqliteAuthorRepository(db *sql.DB) domain.AuthorRepository {
+	return &sqliteAuthorRepo{DB: db}
+}
+
+func (m *sqliteAuthorRepo) getOne(ctx context.Context, query string, args ...interface{}) (res domain.Author, err error) {
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.Author{}, err
+	}
+	defer stmt.Close()
+	row := stmt.QueryRowContext(ctx, args...)
+	res = domain.Author{}
+
+	err = row.Scan(
+		&res.ID,
+		&res.Name,
+		&res.CreatedAt,
+		&res.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return domain.Author{}, domain.ErrNotFound
+	}
+
+	return
+}
+
+func (m *sqliteAuthorRepo) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Author, err error) {
+	rows, err := m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	result = make([]domain.Author, 0)
+	for rows.Next() {
+		t := domain.Author{}
+		err = rows.Scan(
+			&t.ID,
+			&t.Name,
+			&t.CreatedAt,
+			&t.UpdatedAt,
+		)
+
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, nil
+}
+
+func (m *sqliteAuthorRepo) Fetch(ctx context.Context, cursor string, num int64) (res []domain.Author, nextCursor string, err error) {
+	// a negative LIMIT means no limit to SQLite
+	if num <= 0 {
+		return make([]domain.Author, 0), "", nil
+	}
+
+	query := `SELECT id, name, created_at, updated_at FROM author WHERE id > ? ORDER BY id LIMIT ?`
+
+	decodedCursor, err := repository.DecodeCursor(cursor)
+	if err != nil && cursor != "" {
+		return nil, "", domain.ErrBadParamInput
+	}
+
+	res, err = m.fetch(ctx, query, decodedCursor, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(res) == int(num) {
+		nextCursor = repository.EncodeCursor(res[len(res)-1].ID)
+	}
+
+	return
+}
+
+func (m *sqliteAuthorRepo) GetByID(ctx context.Context, id int64) (domain.Author, error) {
+	query := `SELECT id, name, created_at, updated_at FROM author WHERE id=?`
+	return m.getOne(ctx, query, id)
+}
+
+func (m *sqliteAuthorRepo) GetByIDs(ctx context.Context, ids []int64) (map[int64]domain.Author, error) {
+	res := make(map[int64]domain.Author, len(ids))
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	query := fmt.Sprintf(`SELECT id, name, created_at, updated_at FROM author WHERE id IN (%s)`,
+		strings.Join(placeholders, ","))
+
+	list, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, au := range list {
+		res[au.ID] = au
+	}
+	return res, nil
+}
+
+func (m *sqliteAuthorRepo) Store(ctx context.Context, au *domain.Author) (err error) {
+	query := `INSERT INTO author (name, created_at, updated_at) VALUES (?, ?, ?)`
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, au.Name, au.CreatedAt.UTC(), au.UpdatedAt.UTC())
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	au.ID = lastID
+	return
+}
+
+func (m *sqliteAuthorRepo) Update(ctx context.Context, au *domain.Author) (err error) {
+	query := `UPDATE author SET name=?, updated_at=? WHERE id=?`
+
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, au.Name, au.UpdatedAt.UTC(), au.ID)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect == 0 {
+		err = domain.ErrNotFound
+		return
+	}
+	if affect != 1 {
+		err = fmt.Errorf("Weird  Behaviour. Total Affected: %d", affect)
+		return
+	}
+
+	return
+}
+
+func (m *sqliteAuthorRepo) Delete(ctx context.Context, id int64) (err error) {
+	query := `DELETE FROM author WHERE id=?`
+
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+
+	rowsAfected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAfected == 0 {
+		err = domain.ErrNotFound
+		return
+	}
+	if rowsAfected != 1 {
+		err = fmt.Errorf("Weird  Behaviour. Total Affected: %d", rowsAfected)
+		return
+	}
+
+	return
+}
@@ -0,0 +1,77 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/author/repository/repositorytest"
+	"github.com/phantomnat/go-clean-architecture/author/repository/sqlite"
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/migration"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// migrate creates the schema of the service in db
+func migrate(t *testing.T, db *sql.DB) {
+	m, err := migration.NewMigrator(db, "sqlite3")
+	require.NoError(t, err)
+	_, err = m.Up(context.TODO())
+	require.NoError(t, err)
+}
+
+func TestAuthorRepository(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	migrate(t, db)
+
+	a := sqlite.NewSqliteAuthorRepository(db)
+	now := time.Now()
+	for _, name := range []string{"Iron Man", "Thor", "Hulk"} {
+		au := domain.Author{Name: name, CreatedAt: now, UpdatedAt: now}
+		require.NoError(t, a.Store(context.TODO(), &au))
+	}
+
+	list, nextCursor, err := a.Fetch(context.TODO(), "", 2)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+	list, _, err = a.Fetch(context.TODO(), nextCursor, 2)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "Hulk", list[0].Name)
+
+	res, err := a.GetByIDs(context.TODO(), []int64{1, 3, 4})
+	require.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, "Iron Man", res[1].Name)
+
+	au := domain.Author{ID: 2, Name: "Loki", UpdatedAt: time.Now()}
+	require.NoError(t, a.Update(context.TODO(), &au))
+	got, err := a.GetByID(context.TODO(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, "Loki", got.Name)
+
+	require.NoError(t, a.Delete(context.TODO(), 2))
+	_, err = a.GetByID(context.TODO(), 2)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestContract(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	migrate(t, db)
+
+	repositorytest.RunAuthorRepositoryTests(t, func(t *testing.T) domain.AuthorRepository {
+		_, err := db.Exec("DELETE FROM author")
+		require.NoError(t, err)
+		return sqlite.NewSqliteAuthorRepository(db)
+	})
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/logging"
+)
+
+type authorUsecase struct {
+	// contextTimeout is a time.Duration, it is accessed atomically so it can be changed while serving
+	contextTimeout int64
+	authorRepo     domain.AuthorRepository
+	articleRepo    domain.ArticleRepository
+	policy         domain.AuthorPolicy
+}
+
+var _ domain.AuthorUsecase = &authorUsecase{}
+
+// NewAuthorUseCase will create new authorUsecase object representation of domain.AuthorUsecase interface,
+// the articles of the authors are read from given repository and the changes are authorized by given policy
+func NewAuthorUseCase(author domain.AuthorRepository, article domain.ArticleRepository, policy domain.AuthorPolicy,
+	timeout time.Duration) domain.AuthorUsecase {
+	return &authorUsecase{
+		authorRepo:     author,
+		articleRepo:    article,
+		policy:         policy,
+		contextTimeout: int64(timeout),
+	}
+}
+
+// SetContextTimeout changes the timeout of the next calls, the calls in flight keep theirs
+func (a *authorUsecase) SetContextTimeout(timeout time.Duration) {
+	atomic.StoreInt64(&a.contextTimeout, int64(timeout))
+}
+
+func (a *authorUsecase) timeout() time.Duration {
+	return time.Duration(atomic.LoadInt64(&a.contextTimeout))
+}
+
+func (a *authorUsecase) Fetch(c context.Context, cursor string, num int64) (res []domain.Author, nextCursor string, err error) {
+	if num < 0 {
+		return nil, "", domain.ErrBadParamInput
+	}
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.timeout())
+	defer cancel()
+
+	res, nextCursor, err = a.authorRepo.Fetch(ctx, cursor, num)
+	if err != nil {
+		return nil, "", err
+	}
+	return
+}
+
+func (a *authorUsecase) GetByID(c context.Context, id int64) (res domain.Author, err error) {
+	ctx, cancel := context.WithTimeout(c, a.timeout())
+	defer cancel()
+
+	return a.authorRepo.GetByID(ctx, id)
+}
+
+func (a *authorUsecase) Update(c context.Context, au *domain.Author) error {
+	if err := a.policy.Authorize(c, domain.AuthorUpdate, *au); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.timeout())
+	defer cancel()
+
+	existedAuthor, err := a.authorRepo.GetByID(ctx, au.ID)
+	if err != nil {
+		return err
+	}
+
+	au.CreatedAt = existedAuthor.CreatedAt
+	au.UpdatedAt = time.Now()
+	err = a.authorRepo.Update(ctx, au)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).WithField("author_id", au.ID).Debug("author updated")
+	return nil
+}
+
+func (a *authorUsecase) Store(c context.Context, au *domain.Author) error {
+	if err := a.policy.Authorize(c, domain.AuthorCreate, *au); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.timeout())
+	defer cancel()
+
+	now := time.Now()
+	au.CreatedAt = now
+	au.UpdatedAt = now
+	err := a.authorRepo.Store(ctx, au)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).WithField("author_id", au.ID).Debug("author stored")
+	return nil
+}
+
+func (a *authorUsecase) Delete(c context.Context, id int64) error {
+	if err := a.policy.Authorize(c, domain.AuthorDelete, domain.Author{ID: id}); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.timeout())
+	defer cancel()
+
+	_, err := a.authorRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	// the articles would be left without author, they must be deleted or moved first
+	articles, _, _, err := a.articleRepo.Fetch(ctx, domain.ArticleFilter{AuthorID: id, SortBy: domain.ArticleSortCreatedAt},
+		"", 1, domain.DirectionNext, domain.SortAsc)
+	if err != nil {
+		return err
+	}
+	if len(articles) > 0 {
+		return domain.ErrAuthorHasArticles
+	}
+	err = a.authorRepo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).WithField("author_id", id).Debug("author deleted")
+	return nil
+}
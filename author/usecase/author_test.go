@@ -0,0 +1,145 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/author/usecase"
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/domain/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFetch(t *testing.T) {
+	mockAuthorRepo := new(mocks.AuthorRepository)
+	mockListAuthor := []domain.Author{{ID: 1, Name: "Iron Man"}}
+
+	t.Run("success", func(t *testing.T) {
+		mockAuthorRepo.On("Fetch", mock.Anything, "", int64(10)).
+			Return(mockListAuthor, "next-cursor", nil).Once()
+		u := usecase.NewAuthorUseCase(mockAuthorRepo, new(mocks.ArticleRepository), usecase.NewUnrestrictedAuthorPolicy(), time.Second*2)
+
+		list, nextCursor, err := u.Fetch(context.TODO(), "", 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "next-cursor", nextCursor)
+		assert.Len(t, list, len(mockListAuthor))
+		mockAuthorRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-failed", func(t *testing.T) {
+		mockAuthorRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("int64")).
+			Return(nil, "", errors.New("unexpected error")).Once()
+		u := usecase.NewAuthorUseCase(mockAuthorRepo, new(mocks.ArticleRepository), usecase.NewUnrestrictedAuthorPolicy(), time.Second*2)
+
+		list, nextCursor, err := u.Fetch(context.TODO(), "", 1)
+
+		assert.Error(t, err)
+		assert.Empty(t, nextCursor)
+		assert.Len(t, list, 0)
+		mockAuthorRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-negative-num", func(t *testing.T) {
+		u := usecase.NewAuthorUseCase(mockAuthorRepo, new(mocks.ArticleRepository), usecase.NewUnrestrictedAuthorPolicy(), time.Second*2)
+
+		_, _, err := u.Fetch(context.TODO(), "", -1)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockAuthorRepo.AssertExpectations(t)
+	})
+}
+
+func TestStore(t *testing.T) {
+	mockAuthorRepo := new(mocks.AuthorRepository)
+	mockAuthor := domain.Author{Name: "Iron Man"}
+
+	mockAuthorRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Author")).Return(nil).Once()
+	u := usecase.NewAuthorUseCase(mockAuthorRepo, new(mocks.ArticleRepository), usecase.NewUnrestrictedAuthorPolicy(), time.Second*2)
+
+	err := u.Store(context.TODO(), &mockAuthor)
+
+	assert.NoError(t, err)
+	assert.False(t, mockAuthor.CreatedAt.IsZero())
+	assert.Equal(t, mockAuthor.CreatedAt, mockAuthor.UpdatedAt)
+	mockAuthorRepo.AssertExpectations(t)
+}
+
+func TestUpdate(t *testing.T) {
+	mockAuthorRepo := new(mocks.AuthorRepository)
+	createdAt := time.Now().Add(-time.Hour)
+	mockAuthor := domain.Author{ID: 1, Name: "Iron Man"}
+
+	t.Run("success", func(t *testing.T) {
+		tempMockAuthor := mockAuthor
+		mockAuthorRepo.On("GetByID", mock.Anything, mockAuthor.ID).
+			Return(domain.Author{ID: 1, Name: "Tony Stark", CreatedAt: createdAt}, nil).Once()
+		mockAuthorRepo.On("Update", mock.Anything, &tempMockAuthor).Return(nil).Once()
+		u := usecase.NewAuthorUseCase(mockAuthorRepo, new(mocks.ArticleRepository), usecase.NewUnrestrictedAuthorPolicy(), time.Second*2)
+
+		err := u.Update(context.TODO(), &tempMockAuthor)
+
+		assert.NoError(t, err)
+		assert.Equal(t, createdAt, tempMockAuthor.CreatedAt)
+		mockAuthorRepo.AssertExpectations(t)
+	})
+
+	t.Run("author is not exist", func(t *testing.T) {
+		tempMockAuthor := mockAuthor
+		mockAuthorRepo.On("GetByID", mock.Anything, mockAuthor.ID).
+			Return(domain.Author{}, domain.ErrNotFound).Once()
+		u := usecase.NewAuthorUseCase(mockAuthorRepo, new(mocks.ArticleRepository), usecase.NewUnrestrictedAuthorPolicy(), time.Second*2)
+
+		err := u.Update(context.TODO(), &tempMockAuthor)
+
+		assert.Equal(t, domain.ErrNotFound, err)
+		mockAuthorRepo.AssertExpectations(t)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	mockAuthorRepo := new(mocks.AuthorRepository)
+	mockArticleRepo := new(mocks.ArticleRepository)
+	byAuthor := domain.ArticleFilter{AuthorID: 1, SortBy: domain.ArticleSortCreatedAt}
+
+	t.Run("success", func(t *testing.T) {
+		mockAuthorRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Author{ID: 1}, nil).Once()
+		mockArticleRepo.On("Fetch", mock.Anything, byAuthor, "", int64(1), domain.DirectionNext, domain.SortAsc).
+			Return(nil, "", "", nil).Once()
+		mockAuthorRepo.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+		u := usecase.NewAuthorUseCase(mockAuthorRepo, mockArticleRepo, usecase.NewUnrestrictedAuthorPolicy(), time.Second*2)
+
+		err := u.Delete(context.TODO(), 1)
+
+		assert.NoError(t, err)
+		mockAuthorRepo.AssertExpectations(t)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("author has articles", func(t *testing.T) {
+		mockAuthorRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Author{ID: 1}, nil).Once()
+		mockArticleRepo.On("Fetch", mock.Anything, byAuthor, "", int64(1), domain.DirectionNext, domain.SortAsc).
+			Return([]domain.Article{{ID: 3, Author: domain.Author{ID: 1}}}, "next", "", nil).Once()
+		u := usecase.NewAuthorUseCase(mockAuthorRepo, mockArticleRepo, usecase.NewUnrestrictedAuthorPolicy(), time.Second*2)
+
+		err := u.Delete(context.TODO(), 1)
+
+		assert.Equal(t, domain.ErrAuthorHasArticles, err)
+		mockAuthorRepo.AssertExpectations(t)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("author is not exist", func(t *testing.T) {
+		mockAuthorRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Author{}, domain.ErrNotFound).Once()
+		u := usecase.NewAuthorUseCase(mockAuthorRepo, mockArticleRepo, usecase.NewUnrestrictedAuthorPolicy(), time.Second*2)
+
+		err := u.Delete(context.TODO(), 1)
+
+		assert.Equal(t, domain.ErrNotFound, err)
+		mockAuthorRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/logging"
+
+	"github.com/sirupsen/logrus"
+)
+
+type rolePolicy struct{}
+
+// NewAuthorPolicy will create the policy granting the changes of the authors to the admins and the editors only,
+// an author may not even change itself
+func NewAuthorPolicy() domain.AuthorPolicy {
+	return rolePolicy{}
+}
+
+func (rolePolicy) Authorize(ctx context.Context, action domain.AuthorAction, au domain.Author) error {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if ok && (p.HasRole(domain.RoleAdmin) || p.HasRole(domain.RoleEditor)) {
+		return nil
+	}
+	logging.FromContext(ctx).WithFields(logrus.Fields{
+		"action":    action,
+		"author_id": au.ID,
+	}).Info("author action denied")
+	return domain.ErrForbidden
+}
+
+type unrestrictedPolicy struct{}
+
+// NewUnrestrictedAuthorPolicy will create the policy allowing every change to everyone, for the trusted callers
+// only, e.g. the commands or a server without authentication
+func NewUnrestrictedAuthorPolicy() domain.AuthorPolicy {
+	return unrestrictedPolicy{}
+}
+
+func (unrestrictedPolicy) Authorize(ctx context.Context, action domain.AuthorAction, au domain.Author) error {
+	return nil
+}
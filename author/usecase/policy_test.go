@@ -0,0 +1,59 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/author/usecase"
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/domain/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *domain.Principal
+		allowed   bool
+	}{
+		{"anonymous", nil, false},
+		{"no role", &domain.Principal{Subject: "someone"}, false},
+		{"author", &domain.Principal{Subject: "someone", Author: domain.Author{ID: 7}, Roles: []string{domain.RoleAuthor}}, false},
+		{"editor", &domain.Principal{Subject: "someone", Roles: []string{domain.RoleEditor}}, true},
+		{"admin", &domain.Principal{Subject: "someone", Roles: []string{domain.RoleAdmin}}, true},
+	}
+
+	policy := usecase.NewAuthorPolicy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			if tt.principal != nil {
+				ctx = domain.ContextWithPrincipal(ctx, *tt.principal)
+			}
+			for _, action := range []domain.AuthorAction{domain.AuthorCreate, domain.AuthorUpdate, domain.AuthorDelete} {
+				err := policy.Authorize(ctx, action, domain.Author{ID: 7})
+				if tt.allowed {
+					assert.NoError(t, err, action)
+				} else {
+					assert.Equal(t, domain.ErrForbidden, err, action)
+				}
+			}
+		})
+	}
+}
+
+func TestForbidden(t *testing.T) {
+	// the repository is not even called
+	mockAuthorRepo := new(mocks.AuthorRepository)
+	u := usecase.NewAuthorUseCase(mockAuthorRepo, new(mocks.ArticleRepository), usecase.NewAuthorPolicy(), time.Second*2)
+	ctx := domain.ContextWithPrincipal(context.TODO(), domain.Principal{
+		Subject: "iman", Author: domain.Author{ID: 7}, Roles: []string{domain.RoleAuthor},
+	})
+
+	assert.Equal(t, domain.ErrForbidden, u.Store(ctx, &domain.Author{Name: "Iron Man"}))
+	assert.Equal(t, domain.ErrForbidden, u.Update(ctx, &domain.Author{ID: 7, Name: "Iron Man"}))
+	assert.Equal(t, domain.ErrForbidden, u.Delete(ctx, 7))
+	mockAuthorRepo.AssertExpectations(t)
+}
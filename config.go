@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/phantomnat/go-clean-architecture/config"
+
+	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// secretKeys are the parts of a setting name whose value is never printed
+var secretKeys = []string{"pass", "secret", "token", "key"}
+
+const maskedValue = "******"
+
+// runConfig implements `config print`
+func runConfig(cfg config.AppConfig, args []string) {
+	if len(args) == 0 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, "Usage: config print")
+		os.Exit(2)
+	}
+
+	if err := printConfig(os.Stdout, cfg); err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+// printConfig writes the loaded configuration as YAML, the secrets are masked
+func printConfig(w io.Writer, cfg config.AppConfig) error {
+	byt, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	var settings map[string]interface{}
+	if err = yaml.Unmarshal(byt, &settings); err != nil {
+		return err
+	}
+
+	byt, err = yaml.Marshal(maskSecrets(settings))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(byt)
+	return err
+}
+
+func maskSecrets(settings map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(settings))
+	for k, v := range settings {
+		// the nested mappings are decoded with keys of any type
+		if nested, ok := v.(map[interface{}]interface{}); ok {
+			m := make(map[string]interface{}, len(nested))
+			for nk, nv := range nested {
+				m[fmt.Sprint(nk)] = nv
+			}
+			res[k] = maskSecrets(m)
+			continue
+		}
+		res[k] = v
+		for _, secret := range secretKeys {
+			if strings.Contains(strings.ToLower(k), secret) && fmt.Sprint(v) != "" {
+				res[k] = maskedValue
+				break
+			}
+		}
+	}
+	return res
+}
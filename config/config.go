@@ -0,0 +1,213 @@
+// Package config holds the typed configuration of the service, read from env.Config over the defaults
+// and validated before anything starts
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/logging"
+	"github.com/phantomnat/go-clean-architecture/tracing"
+)
+
+// Database drivers
+const (
+	DriverMySQL  = "mysql"
+	DriverSqlite = "sqlite3"
+	DriverMemory = "memory"
+)
+
+// AppConfig is the configuration of the service, see config.yaml for the meaning of every setting.
+// The yaml names of the fields are the ones of the settings.
+type AppConfig struct {
+	Debug     bool            `yaml:"debug"`
+	Server    ServerConfig    `yaml:"server"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Timeouts  TimeoutsConfig  `yaml:"timeouts"`
+	Logging   LoggingConfig   `yaml:"logging"`
+	Metrics   MetricsConfig   `yaml:"metrics"`
+	Tracing   TracingConfig   `yaml:"tracing"`
+	Health    HealthConfig    `yaml:"health"`
+	Auth      AuthConfig      `yaml:"auth"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	Features  FeaturesConfig  `yaml:"features"`
+}
+
+// ServerConfig holds the server.* settings
+type ServerConfig struct {
+	Addr            string        `yaml:"addr"`
+	ReadTimeout     time.Duration `yaml:"read_timeout"`
+	WriteTimeout    time.Duration `yaml:"write_timeout"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	ShutdownDelay   time.Duration `yaml:"shutdown_delay"`
+	// TrustedProxies are the addresses or CIDR networks of the reverse proxies trusted to give the address of the
+	// client in X-Forwarded-For
+	TrustedProxies []string `yaml:"trusted_proxies"`
+	// HotReload watches the config files, the changes of the logging, timeouts, rate limits and features apply while serving
+	HotReload bool `yaml:"hot_reload"`
+}
+
+// DatabaseConfig holds the database.* settings
+type DatabaseConfig struct {
+	Driver      string `yaml:"driver"`
+	File        string `yaml:"file"`
+	AutoMigrate bool   `yaml:"auto_migrate"`
+	Host        string `yaml:"host"`
+	Port        int    `yaml:"port"`
+	User        string `yaml:"user"`
+	Pass        string `yaml:"pass"`
+	Name        string `yaml:"name"`
+	// Loc is the time zone the MySQL driver reads the dates in
+	Loc string `yaml:"loc"`
+}
+
+// TimeoutsConfig holds the timeouts.* settings
+type TimeoutsConfig struct {
+	// Usecase bounds every usecase call, the repository calls included
+	Usecase time.Duration `yaml:"usecase"`
+}
+
+// LoggingConfig holds the logging.* settings
+type LoggingConfig struct {
+	Format string `yaml:"format"`
+	Level  string `yaml:"level"`
+}
+
+// MetricsConfig holds the metrics.* settings
+type MetricsConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// TracingConfig holds the tracing.* settings
+type TracingConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	ServiceName string `yaml:"service_name"`
+	Exporter    string `yaml:"exporter"`
+	Endpoint    string `yaml:"endpoint"`
+	File        string `yaml:"file"`
+}
+
+// HealthConfig holds the health.* settings
+type HealthConfig struct {
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// AuthConfig holds the auth.* settings, the tokens are verified by any of the configured keys
+type AuthConfig struct {
+	// Enabled authenticates the bearer tokens
+	Enabled bool `yaml:"enabled"`
+	// APIKeys authenticates the X-API-Key header, with or without the bearer tokens
+	APIKeys bool `yaml:"api_keys"`
+	// Secret is the HS256 shared secret
+	Secret string `yaml:"secret"`
+	// PublicKey is the PEM encoded RSA public key of the RS256 tokens
+	PublicKey string `yaml:"public_key"`
+	JWKSFile  string `yaml:"jwks_file"`
+	Issuer    string `yaml:"issuer"`
+	Audience  string `yaml:"audience"`
+}
+
+// RateLimitConfig holds the rate_limit.* settings
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Default is the limit of the routes without their own
+	Default RateLimit `yaml:"default"`
+	// Routes are the limits of given routes by method and path template, e.g. "GET /article/:id"
+	Routes map[string]RateLimit `yaml:"routes"`
+}
+
+// RateLimit lets a client make Requests every Period, up to Burst of them at once.
+// Burst defaults to Requests, a route without Requests is not limited.
+type RateLimit struct {
+	Requests int           `yaml:"requests"`
+	Period   time.Duration `yaml:"period"`
+	Burst    int           `yaml:"burst"`
+}
+
+// Limit returns the limit of the route, ok is false when its requests are not limited
+func (r RateLimitConfig) Limit(method, route string) (limit RateLimit, ok bool) {
+	if !r.Enabled {
+		return RateLimit{}, false
+	}
+	limit, ok = r.Routes[method+" "+route]
+	if !ok {
+		limit = r.Default
+	}
+	return limit, limit.Requests > 0
+}
+
+// Required tells whether the mutations require a caller authenticated by a token or an API key
+func (a AuthConfig) Required() bool {
+	return a.Enabled || a.APIKeys
+}
+
+// FeaturesConfig holds the features.* flags
+type FeaturesConfig struct {
+	// Search serves GET /articles/search
+	Search bool `yaml:"search"`
+}
+
+// Default returns the configuration used for the settings missing from the config file and the environment
+func Default() AppConfig {
+	return AppConfig{
+		Server: ServerConfig{
+			Addr:            ":8080",
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    10 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 15 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Driver: DriverMySQL,
+			Host:   "localhost",
+			Port:   3306,
+			Loc:    "UTC",
+		},
+		Timeouts: TimeoutsConfig{
+			Usecase: 2 * time.Second,
+		},
+		Logging: LoggingConfig{
+			Format: logging.FormatText,
+			Level:  "info",
+		},
+		Tracing: TracingConfig{
+			ServiceName: "go-clean-architecture",
+			Exporter:    tracing.ExporterOTLP,
+		},
+		Health: HealthConfig{
+			Timeout: 2 * time.Second,
+		},
+		RateLimit: RateLimitConfig{
+			Default: RateLimit{
+				Requests: 100,
+				Period:   time.Minute,
+			},
+		},
+		Features: FeaturesConfig{
+			Search: true,
+		},
+	}
+}
+
+// DSN returns the data source name of the driver, the memory driver has none
+func (d DatabaseConfig) DSN() string {
+	switch d.Driver {
+	case DriverMySQL:
+		val := url.Values{}
+		val.Add("parseTime", "1")
+		val.Add("loc", d.Loc)
+		// report the matched rows on update, an update that changes nothing is not a missing row
+		val.Add("clientFoundRows", "true")
+		addr := d.Host + ":" + strconv.Itoa(d.Port)
+		return fmt.Sprintf("%s:%s@tcp(%s)/%s?%s", d.User, d.Pass, addr, d.Name, val.Encode())
+	case DriverSqlite:
+		val := url.Values{}
+		val.Add("_busy_timeout", "5000")
+		return fmt.Sprintf("file:%s?%s", d.File, val.Encode())
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,259 @@
+package config_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// settings is an env.Config reading a flat map of keys
+type settings map[string]interface{}
+
+func (s settings) GetString(key string) string { return fmt.Sprint(s[key]) }
+func (s settings) GetInt(key string) int       { return s[key].(int) }
+func (s settings) GetBool(key string) bool     { return s[key].(bool) }
+func (s settings) GetDuration(key string) time.Duration {
+	d, _ := time.ParseDuration(s.GetString(key))
+	return d
+}
+func (s settings) IsSet(key string) bool {
+	_, ok := s[key]
+	return ok
+}
+func (s settings) AllSettings() map[string]interface{} { return s }
+func (s settings) Subscribe(fn func())                 {}
+func (s settings) Watch(onError func(error)) (func(), error) {
+	return func() {}, nil
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := config.Load(settings{
+		"database.user": "root",
+		"database.name": "article",
+	})
+	require.NoError(t, err)
+
+	expected := config.Default()
+	expected.Database.User = "root"
+	expected.Database.Name = "article"
+	assert.Equal(t, expected, cfg)
+	assert.Equal(t, 2*time.Second, cfg.Timeouts.Usecase)
+	assert.True(t, cfg.Features.Search)
+}
+
+func TestLoad(t *testing.T) {
+	cfg, err := config.Load(settings{
+		"server.addr":         ":8800",
+		"server.read_timeout": "5s",
+		"database.driver":     "sqlite3",
+		"database.file":       "article.db",
+		"database.port":       "3307",
+		"timeouts.usecase":    "500ms",
+		"logging.format":      "json",
+		"metrics.enabled":     true,
+		"features.search":     false,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, ":8800", cfg.Server.Addr)
+	assert.Equal(t, 5*time.Second, cfg.Server.ReadTimeout)
+	assert.Equal(t, 10*time.Second, cfg.Server.WriteTimeout)
+	assert.Equal(t, config.DriverSqlite, cfg.Database.Driver)
+	assert.Equal(t, 3307, cfg.Database.Port)
+	assert.Equal(t, 500*time.Millisecond, cfg.Timeouts.Usecase)
+	assert.Equal(t, "json", cfg.Logging.Format)
+	assert.True(t, cfg.Metrics.Enabled)
+	assert.False(t, cfg.Features.Search)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	_, err := config.Load(settings{
+		"server.read_timeout":   "10",
+		"server.idle_timeout":   "-1s",
+		"database.driver":       "postgres",
+		"metrics.enabled":       "sure",
+		"logging.level":         "loud",
+		"tracing.enabled":       true,
+		"tracing.exporter":      "otlp",
+		"tracing.endpoint":      "localhost:4318",
+		"timeouts.usecase":      "0s",
+		"database.auto_migrate": "yes",
+	})
+	require.Error(t, err)
+	verr, ok := err.(*config.ValidationError)
+	require.True(t, ok)
+
+	assert.ElementsMatch(t, []string{
+		`server.read_timeout: "10" is not a valid duration, e.g. 10s or 1m30s`,
+		`metrics.enabled: "sure" is not a valid boolean`,
+		`database.auto_migrate: "yes" is not a valid boolean`,
+		`server.idle_timeout: must be greater than 0, got -1s`,
+		`database.driver: must be one of mysql, sqlite3 or memory, got "postgres"`,
+		`timeouts.usecase: must be greater than 0, got 0s`,
+		`logging.level: unknown level "loud"`,
+		`tracing.endpoint: must be the http(s) URL of the OTLP receiver, got "localhost:4318"`,
+	}, verr.Problems)
+}
+
+func TestValidateDatabase(t *testing.T) {
+	cfg := config.Default()
+	cfg.Database = config.DatabaseConfig{Driver: config.DriverMySQL, Port: 70000, Loc: "Mars/Olympus"}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.ElementsMatch(t, []string{
+		"database.host: is required by the mysql driver",
+		"database.port: must be between 1 and 65535, got 70000",
+		"database.user: is required by the mysql driver",
+		"database.name: is required by the mysql driver",
+		`database.loc: unknown time zone "Mars/Olympus"`,
+	}, err.(*config.ValidationError).Problems)
+
+	cfg.Database = config.DatabaseConfig{Driver: config.DriverSqlite}
+	assert.EqualError(t, cfg.Validate(), "invalid configuration:\n  database.file: is required by the sqlite3 driver")
+
+	cfg.Database = config.DatabaseConfig{Driver: config.DriverMemory}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestDSN(t *testing.T) {
+	db := config.DatabaseConfig{
+		Driver: config.DriverMySQL,
+		Host:   "localhost",
+		Port:   3306,
+		User:   "root",
+		Pass:   "secret",
+		Name:   "article",
+		Loc:    "Asia/Bangkok",
+	}
+	assert.Equal(t, "root:secret@tcp(localhost:3306)/article?clientFoundRows=true&loc=Asia%2FBangkok&parseTime=1", db.DSN())
+
+	db = config.DatabaseConfig{Driver: config.DriverSqlite, File: "article.db"}
+	assert.Equal(t, "file:article.db?_busy_timeout=5000", db.DSN())
+
+	assert.Equal(t, "", config.DatabaseConfig{Driver: config.DriverMemory}.DSN())
+}
+
+func TestLoadSecretFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "db_pass")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString("s3cret\n")
+	require.NoError(t, err)
+	f.Close()
+
+	cfg, err := config.Load(settings{
+		"database.user":      "root",
+		"database.name":      "article",
+		"database.pass":      "from-config",
+		"database.pass_file": f.Name(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", cfg.Database.Pass)
+
+	_, err = config.Load(settings{
+		"database.user":      "root",
+		"database.name":      "article",
+		"database.pass_file": f.Name() + ".missing",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database.pass_file: open "+f.Name()+".missing")
+}
+
+func TestValidateAuth(t *testing.T) {
+	cfg := config.Default()
+	cfg.Database = config.DatabaseConfig{Driver: config.DriverMemory}
+	cfg.Auth = config.AuthConfig{Enabled: true}
+	assert.EqualError(t, cfg.Validate(),
+		"invalid configuration:\n  auth: one of auth.secret, auth.public_key or auth.jwks_file is required")
+
+	cfg.Auth.Secret = "short"
+	assert.EqualError(t, cfg.Validate(), "invalid configuration:\n  auth.secret: must be at least 32 bytes, got 5")
+
+	cfg.Auth.Secret = "0123456789abcdef0123456789abcdef"
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestLoadAPIKeys(t *testing.T) {
+	cfg, err := config.Load(settings{
+		"database.driver": "memory",
+		"auth.api_keys":   "true",
+	})
+	require.NoError(t, err)
+	// the API keys need no key of the bearer tokens
+	assert.False(t, cfg.Auth.Enabled)
+	assert.True(t, cfg.Auth.APIKeys)
+	assert.True(t, cfg.Auth.Required())
+	assert.False(t, config.Default().Auth.Required())
+}
+
+func TestLoadRateLimit(t *testing.T) {
+	cfg, err := config.Load(settings{
+		"database.user":                            "root",
+		"database.name":                            "article",
+		"rate_limit.enabled":                       true,
+		"rate_limit.default.requests":              "60",
+		"rate_limit.routes.get /articles.requests": "10",
+		"rate_limit.routes.get /articles.burst":    "5",
+		"rate_limit.routes.post /articles.period":  "1h",
+		"rate_limit.routes.get /healthz.requests":  "0",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, config.RateLimit{Requests: 60, Period: time.Minute}, cfg.RateLimit.Default)
+	assert.Equal(t, map[string]config.RateLimit{
+		"GET /articles":  {Requests: 10, Period: time.Minute, Burst: 5},
+		"POST /articles": {Period: time.Hour},
+		"GET /healthz":   {Period: time.Minute},
+	}, cfg.RateLimit.Routes)
+
+	limit, ok := cfg.RateLimit.Limit("GET", "/articles")
+	assert.True(t, ok)
+	assert.Equal(t, 10, limit.Requests)
+	limit, ok = cfg.RateLimit.Limit("GET", "/authors")
+	assert.True(t, ok)
+	assert.Equal(t, 60, limit.Requests)
+	_, ok = cfg.RateLimit.Limit("GET", "/healthz")
+	assert.False(t, ok)
+
+	cfg.RateLimit.Enabled = false
+	_, ok = cfg.RateLimit.Limit("GET", "/articles")
+	assert.False(t, ok)
+}
+
+func TestValidateRateLimit(t *testing.T) {
+	cfg := config.Default()
+	cfg.Database = config.DatabaseConfig{Driver: config.DriverMemory}
+	cfg.RateLimit.Default = config.RateLimit{Requests: 10, Burst: -1}
+	cfg.RateLimit.Routes = map[string]config.RateLimit{
+		"FETCH /articles": {Requests: -1},
+	}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.ElementsMatch(t, []string{
+		"rate_limit.default.burst: must not be negative, got -1",
+		"rate_limit.default.period: must be greater than 0, got 0s",
+		`rate_limit.routes: "FETCH /articles" must be a method and a path, e.g. GET /articles`,
+		"rate_limit.routes.FETCH /articles.requests: must not be negative, got -1",
+	}, err.(*config.ValidationError).Problems)
+}
+
+func TestLoadTrustedProxies(t *testing.T) {
+	cfg, err := config.Load(settings{
+		"database.user":          "root",
+		"database.name":          "article",
+		"server.trusted_proxies": "10.0.0.0/8, 192.168.1.10,",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.10"}, cfg.Server.TrustedProxies)
+
+	cfg.Server.TrustedProxies = []string{"proxy.local"}
+	assert.EqualError(t, cfg.Validate(),
+		`invalid configuration:`+"\n"+`  server.trusted_proxies: "proxy.local" is not an IP address or a CIDR network`)
+}
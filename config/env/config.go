@@ -1,49 +1,159 @@
 package env
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
+// Prefix of the environment variables overriding the settings, e.g. GO-CLEAN_DATABASE_HOST for database.host
+const Prefix = "go-clean"
+
+// DefaultFile is the config file read when no file is given, it is optional
+const DefaultFile = "config.yaml"
+
 type Config interface {
 	GetString(key string) string
 	GetInt(key string) int
 	GetBool(key string) bool
-	Init()
+	GetDuration(key string) time.Duration
+	IsSet(key string) bool
+	AllSettings() map[string]interface{}
+	// Subscribe registers fn, it is called after every reload of the settings by Watch
+	Subscribe(fn func())
+	// Watch reloads the settings whenever the config files change until stop is called,
+	// a failed reload keeps the previous settings and is given to onError
+	Watch(onError func(error)) (stop func(), err error)
+}
+
+// Options selects the files the settings are read from
+type Options struct {
+	// File is the base config file, DefaultFile when empty. Unlike the default one, a given file must exist.
+	File string
+	// Env names the overlay merged over the base file, e.g. prod merges config.prod.yaml next to config.yaml
+	Env string
+}
+
+// OverlayFile returns the path of the overlay of env next to file, e.g. config.prod.yaml for config.yaml
+func OverlayFile(file, env string) string {
+	ext := filepath.Ext(file)
+	return strings.TrimSuffix(file, ext) + "." + env + ext
 }
 
 type viperConfig struct {
+	opts Options
+
+	mu          sync.RWMutex
+	v           *viper.Viper
+	subscribers []func()
 }
 
-func (v *viperConfig) Init() {
-	viper.SetEnvPrefix("go-clean")
-	viper.AutomaticEnv()
+// NewViperConfig will read the settings from the files of opts, the environment variables take precedence over them
+func NewViperConfig(opts Options) (Config, error) {
+	v, err := readViper(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &viperConfig{opts: opts, v: v}, nil
+}
+
+// files returns the config files of opts, the overlay comes last
+func (o Options) files() []string {
+	file := o.File
+	if file == "" {
+		file = DefaultFile
+	}
+	if o.Env == "" {
+		return []string{file}
+	}
+	return []string{file, OverlayFile(file, o.Env)}
+}
 
+func readViper(opts Options) (*viper.Viper, error) {
+	v := viper.New()
+	v.SetEnvPrefix(Prefix)
+	v.AutomaticEnv()
 	replacer := strings.NewReplacer(".", "_")
-	viper.SetEnvKeyReplacer(replacer)
-	viper.SetConfigType("yaml")
-	viper.SetConfigFile("config.yaml")
-	err := viper.ReadInConfig()
-	if err != nil {
-		panic(err)
+	v.SetEnvKeyReplacer(replacer)
+	v.SetConfigType("yaml")
+
+	files := opts.files()
+	file := files[0]
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		// without any file the settings come from the environment and the defaults only
+		if opts.File != "" || !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading config file %s: %v", file, err)
+		}
+	}
+
+	if len(files) > 1 {
+		overlay := files[1]
+		v.SetConfigFile(overlay)
+		if err := v.MergeInConfig(); err != nil {
+			return nil, fmt.Errorf("reading config overlay %s: %v", overlay, err)
+		}
 	}
+
+	return v, nil
 }
 
-func (v *viperConfig) GetString(key string) string {
-	return viper.GetString(key)
+// viper returns the settings currently loaded
+func (c *viperConfig) viper() *viper.Viper {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.v
 }
 
-func (v *viperConfig) GetInt(key string) int {
-	return viper.GetInt(key)
+func (c *viperConfig) GetString(key string) string {
+	return c.viper().GetString(key)
 }
 
-func (v *viperConfig) GetBool(key string) bool {
-	return viper.GetBool(key)
+func (c *viperConfig) GetInt(key string) int {
+	return c.viper().GetInt(key)
 }
 
-func NewViperConfig() Config {
-	v := &viperConfig{}
-	v.Init()
-	return v
+func (c *viperConfig) GetBool(key string) bool {
+	return c.viper().GetBool(key)
+}
+
+func (c *viperConfig) GetDuration(key string) time.Duration {
+	return c.viper().GetDuration(key)
+}
+
+func (c *viperConfig) IsSet(key string) bool {
+	return c.viper().IsSet(key)
+}
+
+func (c *viperConfig) AllSettings() map[string]interface{} {
+	return c.viper().AllSettings()
+}
+
+func (c *viperConfig) Subscribe(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subscribers = append(c.subscribers, fn)
+}
+
+// reload reads the files again and notifies the subscribers, the settings are swapped at once
+func (c *viperConfig) reload() error {
+	v, err := readViper(c.opts)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.v = v
+	subscribers := append([]func(){}, c.subscribers...)
+	c.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn()
+	}
+	return nil
 }
@@ -0,0 +1,149 @@
+package env_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/config/env"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const base = `
+server:
+  addr: ":8800"
+  read_timeout: 10s
+database:
+  driver: mysql
+  host: localhost
+`
+
+const overlay = `
+server:
+  addr: ":80"
+database:
+  host: db.prod
+`
+
+// writeFiles writes the files in a temporary directory, it returns the directory
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	for name, content := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	return dir
+}
+
+func TestOverlayFile(t *testing.T) {
+	assert.Equal(t, "config.prod.yaml", env.OverlayFile("config.yaml", "prod"))
+	assert.Equal(t, "/etc/app/app.staging.yml", env.OverlayFile("/etc/app/app.yml", "staging"))
+}
+
+func TestNewViperConfig(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"config.yaml": base, "config.prod.yaml": overlay})
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "config.yaml")
+
+	c, err := env.NewViperConfig(env.Options{File: file})
+	require.NoError(t, err)
+	assert.Equal(t, ":8800", c.GetString("server.addr"))
+	assert.Equal(t, "localhost", c.GetString("database.host"))
+
+	c, err = env.NewViperConfig(env.Options{File: file, Env: "prod"})
+	require.NoError(t, err)
+	// the overlay only replaces the settings it has
+	assert.Equal(t, ":80", c.GetString("server.addr"))
+	assert.Equal(t, "db.prod", c.GetString("database.host"))
+	assert.Equal(t, "10s", c.GetString("server.read_timeout"))
+	assert.Equal(t, "mysql", c.GetString("database.driver"))
+
+	// the environment wins over both files
+	os.Setenv("GO-CLEAN_DATABASE_HOST", "db.override")
+	defer os.Unsetenv("GO-CLEAN_DATABASE_HOST")
+	c, err = env.NewViperConfig(env.Options{File: file, Env: "prod"})
+	require.NoError(t, err)
+	assert.Equal(t, "db.override", c.GetString("database.host"))
+	assert.True(t, c.IsSet("database.host"))
+	assert.False(t, c.IsSet("database.pass"))
+}
+
+func TestNewViperConfigMissingFiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"config.yaml": base})
+	defer os.RemoveAll(dir)
+
+	_, err := env.NewViperConfig(env.Options{File: filepath.Join(dir, "missing.yaml")})
+	assert.Error(t, err)
+
+	_, err = env.NewViperConfig(env.Options{File: filepath.Join(dir, "config.yaml"), Env: "prod"})
+	assert.Error(t, err)
+
+	// the default file is optional, the settings then come from the environment only
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer os.Chdir(wd)
+	require.NoError(t, os.Remove("config.yaml"))
+
+	os.Setenv("GO-CLEAN_DATABASE_DRIVER", "memory")
+	defer os.Unsetenv("GO-CLEAN_DATABASE_DRIVER")
+	c, err := env.NewViperConfig(env.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "memory", c.GetString("database.driver"))
+}
+
+func TestWatch(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"config.yaml": base, "config.prod.yaml": overlay})
+	defer os.RemoveAll(dir)
+
+	c, err := env.NewViperConfig(env.Options{File: filepath.Join(dir, "config.yaml"), Env: "prod"})
+	require.NoError(t, err)
+
+	reloaded := make(chan string, 10)
+	c.Subscribe(func() {
+		reloaded <- c.GetString("server.addr")
+	})
+	errs := make(chan error, 10)
+	stop, err := c.Watch(func(err error) {
+		errs <- err
+	})
+	require.NoError(t, err)
+	defer stop()
+
+	write := func(name, content string) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+
+	write("config.prod.yaml", "server:\n  addr: \":81\"\n")
+	select {
+	case addr := <-reloaded:
+		assert.Equal(t, ":81", addr)
+		// the base file is still merged below the overlay
+		assert.Equal(t, "mysql", c.GetString("database.driver"))
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the settings were not reloaded")
+	}
+
+	write("config.yaml", "server: [")
+	select {
+	case err := <-errs:
+		assert.Error(t, err)
+		assert.Equal(t, ":81", c.GetString("server.addr"))
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the failed reload was not reported")
+	}
+
+	// the other files of the directory are ignored
+	write("unrelated.yaml", "server: [")
+	select {
+	case <-reloaded:
+		require.FailNow(t, "reloaded on an unrelated file")
+	case err := <-errs:
+		require.FailNow(t, "reloaded on an unrelated file", err.Error())
+	case <-time.After(300 * time.Millisecond):
+	}
+}
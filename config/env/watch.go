@@ -0,0 +1,86 @@
+package env
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce groups the events of a single save, editors write a file in several steps
+const watchDebounce = 100 * time.Millisecond
+
+// kubernetesDataDir is the symlink Kubernetes swaps to update the files of a mounted ConfigMap at once
+const kubernetesDataDir = "..data"
+
+func (c *viperConfig) Watch(onError func(error)) (stop func(), err error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	// the directories are watched rather than the files, the editors and Kubernetes replace the files
+	watched := make(map[string]bool)
+	dirs := make(map[string]bool)
+	for _, file := range c.opts.files() {
+		watched[filepath.Clean(file)] = true
+		dir := filepath.Dir(file)
+		if dirs[dir] {
+			continue
+		}
+		dirs[dir] = true
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return nil, err
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		var (
+			debounce *time.Timer
+			reloads  = make(chan struct{}, 1)
+		)
+		for {
+			select {
+			case <-done:
+				if debounce != nil {
+					debounce.Stop()
+				}
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if !watched[filepath.Clean(event.Name)] && filepath.Base(event.Name) != kubernetesDataDir {
+					continue
+				}
+				if debounce != nil {
+					debounce.Stop()
+				}
+				debounce = time.AfterFunc(watchDebounce, func() {
+					select {
+					case reloads <- struct{}{}:
+					default:
+					}
+				})
+			case <-reloads:
+				if err := c.reload(); err != nil && onError != nil {
+					onError(err)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				if onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		watcher.Close()
+	}, nil
+}
@@ -0,0 +1,230 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/config/env"
+)
+
+// Load reads the settings of c over the defaults and validates the result.
+// A setting that cannot be parsed, e.g. a duration without unit, is reported along with the invalid ones.
+func Load(c env.Config) (AppConfig, error) {
+	cfg := Default()
+	l := &loader{config: c}
+
+	l.bool("debug", &cfg.Debug)
+
+	l.string("server.addr", &cfg.Server.Addr)
+	l.duration("server.read_timeout", &cfg.Server.ReadTimeout)
+	l.duration("server.write_timeout", &cfg.Server.WriteTimeout)
+	l.duration("server.idle_timeout", &cfg.Server.IdleTimeout)
+	l.duration("server.shutdown_timeout", &cfg.Server.ShutdownTimeout)
+	l.duration("server.shutdown_delay", &cfg.Server.ShutdownDelay)
+	l.list("server.trusted_proxies", &cfg.Server.TrustedProxies)
+	l.bool("server.hot_reload", &cfg.Server.HotReload)
+
+	l.string("database.driver", &cfg.Database.Driver)
+	l.string("database.file", &cfg.Database.File)
+	l.bool("database.auto_migrate", &cfg.Database.AutoMigrate)
+	l.string("database.host", &cfg.Database.Host)
+	l.int("database.port", &cfg.Database.Port)
+	l.string("database.user", &cfg.Database.User)
+	l.secret("database.pass", &cfg.Database.Pass)
+	l.string("database.name", &cfg.Database.Name)
+	l.string("database.loc", &cfg.Database.Loc)
+
+	l.duration("timeouts.usecase", &cfg.Timeouts.Usecase)
+
+	l.string("logging.format", &cfg.Logging.Format)
+	l.string("logging.level", &cfg.Logging.Level)
+
+	l.bool("metrics.enabled", &cfg.Metrics.Enabled)
+
+	l.bool("tracing.enabled", &cfg.Tracing.Enabled)
+	l.string("tracing.service_name", &cfg.Tracing.ServiceName)
+	l.string("tracing.exporter", &cfg.Tracing.Exporter)
+	l.string("tracing.endpoint", &cfg.Tracing.Endpoint)
+	l.string("tracing.file", &cfg.Tracing.File)
+
+	l.duration("health.timeout", &cfg.Health.Timeout)
+
+	l.bool("auth.enabled", &cfg.Auth.Enabled)
+	l.bool("auth.api_keys", &cfg.Auth.APIKeys)
+	l.secret("auth.secret", &cfg.Auth.Secret)
+	l.secret("auth.public_key", &cfg.Auth.PublicKey)
+	l.string("auth.jwks_file", &cfg.Auth.JWKSFile)
+	l.string("auth.issuer", &cfg.Auth.Issuer)
+	l.string("auth.audience", &cfg.Auth.Audience)
+
+	l.bool("rate_limit.enabled", &cfg.RateLimit.Enabled)
+	l.rateLimit("rate_limit.default", &cfg.RateLimit.Default)
+	for _, route := range l.keys("rate_limit.routes") {
+		// the keys are lowercased, the method is not
+		limit := RateLimit{Period: cfg.RateLimit.Default.Period}
+		l.rateLimit("rate_limit.routes."+route, &limit)
+		if cfg.RateLimit.Routes == nil {
+			cfg.RateLimit.Routes = make(map[string]RateLimit)
+		}
+		cfg.RateLimit.Routes[routeKey(route)] = limit
+	}
+
+	l.bool("features.search", &cfg.Features.Search)
+
+	problems := append(l.problems, cfg.problems()...)
+	if len(problems) > 0 {
+		return cfg, &ValidationError{Problems: problems}
+	}
+	return cfg, nil
+}
+
+// loader overwrites the defaults with the settings which are set, it keeps the ones it cannot parse as problems
+type loader struct {
+	config   env.Config
+	problems []string
+}
+
+// lookup returns the raw value of the key, ok is false when the key is not set
+func (l *loader) lookup(key string) (value string, ok bool) {
+	if !l.config.IsSet(key) {
+		return "", false
+	}
+	return strings.TrimSpace(l.config.GetString(key)), true
+}
+
+func (l *loader) invalid(key, value, kind string) {
+	l.problems = append(l.problems, fmt.Sprintf("%s: %q is not a valid %s", key, value, kind))
+}
+
+// keys returns the names of the settings under key, e.g. the routes of rate_limit.routes
+func (l *loader) keys(key string) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, k := range flatten("", l.config.AllSettings()) {
+		if !strings.HasPrefix(k, key+".") {
+			continue
+		}
+		// the name is followed by the name of a setting, e.g. rate_limit.routes.get /articles.requests
+		name := k[len(key)+1:]
+		if i := strings.LastIndex(name, "."); i > 0 {
+			name = name[:i]
+		}
+		if !seen[name] {
+			seen[name] = true
+			keys = append(keys, name)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// flatten returns the keys of the nested settings joined by dots
+func flatten(prefix string, settings map[string]interface{}) []string {
+	var keys []string
+	for k, v := range settings {
+		if nested, ok := v.(map[string]interface{}); ok {
+			keys = append(keys, flatten(prefix+k+".", nested)...)
+			continue
+		}
+		keys = append(keys, prefix+k)
+	}
+	return keys
+}
+
+// routeKey returns the route of a rate_limit.routes key with its method in upper case, e.g. GET /articles
+func routeKey(key string) string {
+	fields := strings.Fields(key)
+	if len(fields) == 0 {
+		return key
+	}
+	fields[0] = strings.ToUpper(fields[0])
+	return strings.Join(fields, " ")
+}
+
+func (l *loader) rateLimit(key string, dst *RateLimit) {
+	l.int(key+".requests", &dst.Requests)
+	l.duration(key+".period", &dst.Period)
+	l.int(key+".burst", &dst.Burst)
+}
+
+func (l *loader) string(key string, dst *string) {
+	if v, ok := l.lookup(key); ok {
+		*dst = v
+	}
+}
+
+// secret reads the value of key from the file named by key_file when it is set, e.g. a Docker or Kubernetes
+// secret mounted in the container. The file takes precedence over the value of key.
+func (l *loader) secret(key string, dst *string) {
+	l.string(key, dst)
+
+	path, ok := l.lookup(key + "_file")
+	if !ok || path == "" {
+		return
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		l.problems = append(l.problems, fmt.Sprintf("%s_file: %v", key, err))
+		return
+	}
+	// the editors and `echo` leave a trailing newline the secret does not have
+	*dst = strings.TrimRight(string(b), "\r\n")
+}
+
+// list reads the comma separated values of key, e.g. a list of addresses
+func (l *loader) list(key string, dst *[]string) {
+	v, ok := l.lookup(key)
+	if !ok {
+		return
+	}
+	var values []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			values = append(values, s)
+		}
+	}
+	*dst = values
+}
+
+func (l *loader) bool(key string, dst *bool) {
+	v, ok := l.lookup(key)
+	if !ok {
+		return
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		l.invalid(key, v, "boolean")
+		return
+	}
+	*dst = b
+}
+
+func (l *loader) int(key string, dst *int) {
+	v, ok := l.lookup(key)
+	if !ok {
+		return
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		l.invalid(key, v, "integer")
+		return
+	}
+	*dst = i
+}
+
+func (l *loader) duration(key string, dst *time.Duration) {
+	v, ok := l.lookup(key)
+	if !ok {
+		return
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		l.invalid(key, v, "duration, e.g. 10s or 1m30s")
+		return
+	}
+	*dst = d
+}
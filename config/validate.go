@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/auth"
+	"github.com/phantomnat/go-clean-architecture/logging"
+	"github.com/phantomnat/go-clean-architecture/ratelimit"
+	"github.com/phantomnat/go-clean-architecture/tracing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// httpMethods are the methods of the routes
+var httpMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true,
+	http.MethodPatch: true, http.MethodDelete: true, http.MethodOptions: true,
+}
+
+// ValidationError lists every problem of the configuration, so they can all be fixed at once
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// Validate checks the configuration, it returns a *ValidationError
+func (c AppConfig) Validate() error {
+	if problems := c.problems(); len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func (c AppConfig) problems() []string {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+	positive := func(key string, d time.Duration) {
+		check(d > 0, "%s: must be greater than 0, got %s", key, d)
+	}
+
+	check(c.Server.Addr != "", "server.addr: is required")
+	positive("server.read_timeout", c.Server.ReadTimeout)
+	positive("server.write_timeout", c.Server.WriteTimeout)
+	positive("server.idle_timeout", c.Server.IdleTimeout)
+	positive("server.shutdown_timeout", c.Server.ShutdownTimeout)
+	check(c.Server.ShutdownDelay >= 0, "server.shutdown_delay: must not be negative, got %s", c.Server.ShutdownDelay)
+	_, err := ratelimit.ParseProxies(c.Server.TrustedProxies)
+	check(err == nil, "server.trusted_proxies: %v", err)
+
+	db := c.Database
+	switch db.Driver {
+	case DriverMySQL:
+		check(db.Host != "", "database.host: is required by the mysql driver")
+		check(db.Port > 0 && db.Port < 65536, "database.port: must be between 1 and 65535, got %d", db.Port)
+		check(db.User != "", "database.user: is required by the mysql driver")
+		check(db.Name != "", "database.name: is required by the mysql driver")
+		_, err := time.LoadLocation(db.Loc)
+		check(err == nil, "database.loc: unknown time zone %q", db.Loc)
+	case DriverSqlite:
+		check(db.File != "", "database.file: is required by the sqlite3 driver")
+	case DriverMemory:
+	default:
+		check(false, "database.driver: must be one of mysql, sqlite3 or memory, got %q", db.Driver)
+	}
+
+	positive("timeouts.usecase", c.Timeouts.Usecase)
+	positive("health.timeout", c.Health.Timeout)
+
+	check(c.Logging.Format == logging.FormatText || c.Logging.Format == logging.FormatJSON,
+		"logging.format: must be text or json, got %q", c.Logging.Format)
+	_, err = logrus.ParseLevel(c.Logging.Level)
+	check(err == nil, "logging.level: unknown level %q", c.Logging.Level)
+
+	if t := c.Tracing; t.Enabled {
+		check(t.ServiceName != "", "tracing.service_name: is required")
+		switch t.Exporter {
+		case tracing.ExporterOTLP:
+			u, err := url.Parse(t.Endpoint)
+			check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+				"tracing.endpoint: must be the http(s) URL of the OTLP receiver, got %q", t.Endpoint)
+		case tracing.ExporterStdout:
+		case tracing.ExporterFile:
+			check(t.File != "", "tracing.file: is required by the file exporter")
+		default:
+			check(false, "tracing.exporter: must be one of otlp, stdout or file, got %q", t.Exporter)
+		}
+	}
+
+	if a := c.Auth; a.Enabled {
+		check(a.Secret != "" || a.PublicKey != "" || a.JWKSFile != "",
+			"auth: one of auth.secret, auth.public_key or auth.jwks_file is required")
+		check(a.Secret == "" || len(a.Secret) >= auth.MinSecretLength,
+			"auth.secret: must be at least %d bytes, got %d", auth.MinSecretLength, len(a.Secret))
+	}
+	rateLimit := func(key string, r RateLimit) {
+		check(r.Requests >= 0, "%s.requests: must not be negative, got %d", key, r.Requests)
+		check(r.Burst >= 0, "%s.burst: must not be negative, got %d", key, r.Burst)
+		if r.Requests > 0 {
+			positive(key+".period", r.Period)
+		}
+	}
+	rateLimit("rate_limit.default", c.RateLimit.Default)
+	routes := make([]string, 0, len(c.RateLimit.Routes))
+	for route := range c.RateLimit.Routes {
+		routes = append(routes, route)
+	}
+	// in a stable order, the problems are listed the same every time
+	sort.Strings(routes)
+	for _, route := range routes {
+		r := c.RateLimit.Routes[route]
+		fields := strings.Fields(route)
+		check(len(fields) == 2 && httpMethods[fields[0]] && strings.HasPrefix(fields[1], "/"),
+			"rate_limit.routes: %q must be a method and a path, e.g. GET /articles", route)
+		rateLimit("rate_limit.routes."+route, r)
+	}
+	return problems
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/phantomnat/go-clean-architecture/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestPrintConfig(t *testing.T) {
+	cfg := config.Default()
+	cfg.Database.Pass = "123456"
+	cfg.Auth.PublicKey = "-----BEGIN PUBLIC KEY-----"
+	cfg.RateLimit.Routes = map[string]config.RateLimit{"GET /article/:id": {Requests: 10}}
+
+	var buf bytes.Buffer
+	require.NoError(t, printConfig(&buf, cfg))
+	assert.NotContains(t, buf.String(), "123456")
+	assert.NotContains(t, buf.String(), "BEGIN PUBLIC KEY")
+
+	var printed struct {
+		Server struct {
+			Addr        string `yaml:"addr"`
+			ReadTimeout string `yaml:"read_timeout"`
+		} `yaml:"server"`
+		Database  map[string]interface{} `yaml:"database"`
+		Auth      map[string]interface{} `yaml:"auth"`
+		RateLimit struct {
+			Routes map[string]map[string]interface{} `yaml:"routes"`
+		} `yaml:"rate_limit"`
+	}
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &printed))
+	// the defaults are printed along with the loaded settings
+	assert.Equal(t, ":8080", printed.Server.Addr)
+	assert.Equal(t, "10s", printed.Server.ReadTimeout)
+	assert.Equal(t, "mysql", printed.Database["driver"])
+	assert.Equal(t, 10, printed.RateLimit.Routes["GET /article/:id"]["requests"])
+	// the secrets are masked, unless empty
+	assert.Equal(t, maskedValue, printed.Database["pass"])
+	assert.Equal(t, maskedValue, printed.Auth["public_key"])
+	assert.Equal(t, "", printed.Auth["secret"])
+}
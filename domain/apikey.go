@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrInvalidAPIKey is returned for an API key which is unknown, expired or revoked
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// APIKeyScope represents what an API key gives access to
+type APIKeyScope string
+
+const (
+	ScopeArticlesRead  APIKeyScope = "articles:read"
+	ScopeArticlesWrite APIKeyScope = "articles:write"
+	ScopeAuthorsManage APIKeyScope = "authors:manage"
+)
+
+// IsValid reports whether the scope is a known value
+func (s APIKeyScope) IsValid() bool {
+	return s == ScopeArticlesRead || s == ScopeArticlesWrite || s == ScopeAuthorsManage
+}
+
+// APIKey represents the key of a machine client. Only the hash of the key is stored, the key itself is
+// given once when it is issued.
+type APIKey struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// Prefix is the start of the key, it tells the keys apart in the lists and the logs
+	Prefix string        `json:"prefix"`
+	Hash   string        `json:"-"`
+	Scopes []APIKeyScope `json:"scopes"`
+	// AuthorID is the author the client acts as, 0 when it acts for every author
+	AuthorID   int64      `json:"author_id,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the key is neither revoked nor expired at given time
+func (k APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// APIKeyUsecase represents the API key's usecases, only an admin may manage the keys
+type APIKeyUsecase interface {
+	Fetch(ctx context.Context, cursor string, num int64) ([]APIKey, string, error)
+	// Issue stores the key and returns its secret value, the only time it is known
+	Issue(ctx context.Context, k *APIKey) (string, error)
+	Revoke(ctx context.Context, id int64) error
+	// Authenticate returns the principal of the key, or ErrInvalidAPIKey
+	Authenticate(ctx context.Context, key string) (Principal, error)
+}
+
+// APIKeyRepository represents the API key's repository contract
+type APIKeyRepository interface {
+	Fetch(ctx context.Context, cursor string, num int64) (res []APIKey, nextCursor string, err error)
+	GetByID(ctx context.Context, id int64) (APIKey, error)
+	GetByHash(ctx context.Context, hash string) (APIKey, error)
+	Store(ctx context.Context, k *APIKey) error
+	// Revoke records the first revocation time of the key, revoking it again changes nothing
+	Revoke(ctx context.Context, id int64, at time.Time) error
+	UpdateLastUsed(ctx context.Context, id int64, at time.Time) error
+}
@@ -8,29 +8,78 @@ import (
 // Article
 type Article struct {
 	ID        int64     `json:"id"`
-	Title     string    `json:"title" validate:"required"`
-	Content   string    `json:"content" validate:"required"`
-	Author    Author    `json:"author"`
+	Title     string    `json:"title" validate:"required,max=255"`
+	Content   string    `json:"content" validate:"required,maxbytes=65535"`
+	Author    Author    `json:"author" validate:"-"`
 	UpdatedAt time.Time `json:"updated_at"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// ArticleSortField represents the column an article list is sorted by
+type ArticleSortField string
+
+const (
+	ArticleSortCreatedAt ArticleSortField = "created_at"
+	ArticleSortUpdatedAt ArticleSortField = "updated_at"
+)
+
+// IsValid reports whether the sort field is a known value
+func (f ArticleSortField) IsValid() bool {
+	return f == ArticleSortCreatedAt || f == ArticleSortUpdatedAt
+}
+
+// ArticleFilter represents the criteria used to list the articles.
+// Zero values are ignored, the date ranges include the lower bound and exclude the upper bound.
+type ArticleFilter struct {
+	AuthorID    int64
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	UpdatedFrom time.Time
+	UpdatedTo   time.Time
+	TitlePrefix string
+	SortBy      ArticleSortField
+}
+
+// ArticleSearchResult represents an article matched by a full-text search
+type ArticleSearchResult struct {
+	Article
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"`
+}
+
 // ArticleUsecase represents the article's usecases
 type ArticleUsecase interface {
-	Fetch(ctx context.Context, cursor string, num int64) ([]Article, string, error)
+	Fetch(ctx context.Context, filter ArticleFilter, cursor string, num int64, direction Direction, order SortOrder) ([]Article, string, string, error)
 	GetByID(ctx context.Context, id int64) (Article, error)
 	Update(ctx context.Context, ar *Article) error
 	GetByTitle(ctx context.Context, title string) (Article, error)
+	Search(ctx context.Context, query string, cursor string, num int64) ([]ArticleSearchResult, string, error)
 	Store(ctx context.Context, ar *Article) error
 	Delete(ctx context.Context, id int64) error
 }
 
 // ArticleRepository represent the article's repository contract
 type ArticleRepository interface {
-	Fetch(ctx context.Context, cursor string, num int64) (res []Article, nextCursor string, err error)
+	Fetch(ctx context.Context, filter ArticleFilter, cursor string, num int64, direction Direction, order SortOrder) (res []Article, nextCursor string, prevCursor string, err error)
 	GetByID(ctx context.Context, id int64) (Article, error)
 	GetByTitle(ctx context.Context, title string) (Article, error)
+	Search(ctx context.Context, query string, cursor string, num int64) (res []ArticleSearchResult, nextCursor string, err error)
 	Update(ctx context.Context, ar *Article) error
 	Store(ctx context.Context, a *Article) error
 	Delete(ctx context.Context, id int64) error
 }
+
+// ArticleAction represents what a principal does with an article
+type ArticleAction string
+
+const (
+	ArticleRead   ArticleAction = "read"
+	ArticleCreate ArticleAction = "create"
+	ArticleUpdate ArticleAction = "update"
+	ArticleDelete ArticleAction = "delete"
+)
+
+// ArticlePolicy decides whether the principal of the context may act on an article, it returns ErrForbidden otherwise
+type ArticlePolicy interface {
+	Authorize(ctx context.Context, action ArticleAction, ar Article) error
+}
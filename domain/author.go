@@ -1,16 +1,47 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Author represents the author domain
 type Author struct {
-	ID        int64  `json:"id"`
-	Name      string `json:"name"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID        int64     `json:"id"`
+	Name      string    `json:"name" validate:"required,max=255"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AuthorUsecase represents the author's usecases
+type AuthorUsecase interface {
+	Fetch(ctx context.Context, cursor string, num int64) ([]Author, string, error)
+	GetByID(ctx context.Context, id int64) (Author, error)
+	Update(ctx context.Context, au *Author) error
+	Store(ctx context.Context, au *Author) error
+	Delete(ctx context.Context, id int64) error
 }
 
 // AuthorRepository represents the author's repository contract
 type AuthorRepository interface {
+	Fetch(ctx context.Context, cursor string, num int64) (res []Author, nextCursor string, err error)
 	GetByID(ctx context.Context, id int64) (Author, error)
+	GetByIDs(ctx context.Context, ids []int64) (map[int64]Author, error)
+	Update(ctx context.Context, au *Author) error
+	Store(ctx context.Context, au *Author) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// AuthorAction represents what a principal does with an author
+type AuthorAction string
+
+const (
+	AuthorCreate AuthorAction = "create"
+	AuthorUpdate AuthorAction = "update"
+	AuthorDelete AuthorAction = "delete"
+)
+
+// AuthorPolicy decides whether the principal of the context may change an author, it returns ErrForbidden otherwise
+type AuthorPolicy interface {
+	Authorize(ctx context.Context, action AuthorAction, au Author) error
 }
@@ -7,4 +7,7 @@ var (
 	ErrNotFound       = errors.New("your requested item is not found")
 	ErrAlreadyExist   = errors.New("your item already exist")
 	ErrBadParamInput  = errors.New("given param is not valid")
+	ErrForbidden      = errors.New("you are not allowed to perform this action")
+	// ErrAuthorHasArticles is the error of deleting an author before its articles
+	ErrAuthorHasArticles = errors.New("the author still has articles")
 )
@@ -25,13 +25,13 @@ func (_m *ArticleRepository) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *ArticleRepository) Fetch(ctx context.Context, cursor string, num int64) ([]domain.Article, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// Fetch provides a mock function with given fields: ctx, filter, cursor, num, direction, order
+func (_m *ArticleRepository) Fetch(ctx context.Context, filter domain.ArticleFilter, cursor string, num int64, direction domain.Direction, order domain.SortOrder) ([]domain.Article, string, string, error) {
+	ret := _m.Called(ctx, filter, cursor, num, direction, order)
 
 	var r0 []domain.Article
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []domain.Article); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ArticleFilter, string, int64, domain.Direction, domain.SortOrder) []domain.Article); ok {
+		r0 = rf(ctx, filter, cursor, num, direction, order)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Article)
@@ -39,20 +39,27 @@ func (_m *ArticleRepository) Fetch(ctx context.Context, cursor string, num int64
 	}
 
 	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
-		r1 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ArticleFilter, string, int64, domain.Direction, domain.SortOrder) string); ok {
+		r1 = rf(ctx, filter, cursor, num, direction, order)
 	} else {
 		r1 = ret.Get(1).(string)
 	}
 
-	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
-		r2 = rf(ctx, cursor, num)
+	var r2 string
+	if rf, ok := ret.Get(2).(func(context.Context, domain.ArticleFilter, string, int64, domain.Direction, domain.SortOrder) string); ok {
+		r2 = rf(ctx, filter, cursor, num, direction, order)
 	} else {
-		r2 = ret.Error(2)
+		r2 = ret.Get(2).(string)
 	}
 
-	return r0, r1, r2
+	var r3 error
+	if rf, ok := ret.Get(3).(func(context.Context, domain.ArticleFilter, string, int64, domain.Direction, domain.SortOrder) error); ok {
+		r3 = rf(ctx, filter, cursor, num, direction, order)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
 }
 
 // GetByID provides a mock function with given fields: ctx, id
@@ -97,6 +104,36 @@ func (_m *ArticleRepository) GetByTitle(ctx context.Context, title string) (doma
 	return r0, r1
 }
 
+// Search provides a mock function with given fields: ctx, query, cursor, num
+func (_m *ArticleRepository) Search(ctx context.Context, query string, cursor string, num int64) ([]domain.ArticleSearchResult, string, error) {
+	ret := _m.Called(ctx, query, cursor, num)
+
+	var r0 []domain.ArticleSearchResult
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) []domain.ArticleSearchResult); ok {
+		r0 = rf(ctx, query, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ArticleSearchResult)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) string); ok {
+		r1 = rf(ctx, query, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, int64) error); ok {
+		r2 = rf(ctx, query, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // Store provides a mock function with given fields: ctx, a
 func (_m *ArticleRepository) Store(ctx context.Context, a *domain.Article) error {
 	ret := _m.Called(ctx, a)
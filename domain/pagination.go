@@ -0,0 +1,31 @@
+package domain
+
+// Direction represents which page is requested relative to the given cursor
+type Direction string
+
+const (
+	// DirectionNext requests the items after the cursor
+	DirectionNext Direction = "next"
+	// DirectionPrev requests the items before the cursor
+	DirectionPrev Direction = "prev"
+)
+
+// IsValid reports whether the direction is a known value
+func (d Direction) IsValid() bool {
+	return d == DirectionNext || d == DirectionPrev
+}
+
+// SortOrder represents the order of a paginated list
+type SortOrder string
+
+const (
+	// SortAsc lists the oldest items first
+	SortAsc SortOrder = "asc"
+	// SortDesc lists the newest items first
+	SortDesc SortOrder = "desc"
+)
+
+// IsValid reports whether the sort order is a known value
+func (o SortOrder) IsValid() bool {
+	return o == SortAsc || o == SortDesc
+}
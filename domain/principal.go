@@ -0,0 +1,63 @@
+package domain
+
+import "context"
+
+// Roles of the principals
+const (
+	// RoleAdmin may do anything
+	RoleAdmin = "admin"
+	// RoleEditor may edit the articles of every author
+	RoleEditor = "editor"
+	// RoleAuthor may write its own articles
+	RoleAuthor = "author"
+)
+
+// Principal represents the authenticated caller of a request
+type Principal struct {
+	// Subject identifies the caller for the issuer of its credentials
+	Subject string
+	// Author is the author the caller acts as, its ID is 0 when the caller is not an author
+	Author Author
+	Roles  []string
+	// APIKeyID is the key the caller authenticated with, 0 for the other credentials
+	APIKeyID int64
+	// Scopes limit what the caller of an API key may do
+	Scopes []APIKeyScope
+}
+
+// HasRole reports whether the principal was granted the role
+func (p Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows reports whether the scopes of the API key of the principal grant the scope,
+// the principals of the other credentials are only limited by their roles
+func (p Principal) Allows(scope APIKeyScope) bool {
+	if p.APIKeyID == 0 {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// ContextWithPrincipal returns a copy of ctx carrying the principal
+func ContextWithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal carried by ctx, ok is false for an anonymous request
+func PrincipalFromContext(ctx context.Context) (p Principal, ok bool) {
+	p, ok = ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
@@ -0,0 +1,49 @@
+// Package ginroute tells the path template of the route that matched a request,
+// gin does not expose the matched path of the request.
+package ginroute
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Unmatched is the route of the requests no route matched, e.g. a 404 on an unknown path
+const Unmatched = "unmatched"
+
+// Resolver finds the path template, e.g. /articles/:id, of the route serving a request
+type Resolver struct {
+	engine *gin.Engine
+	once   sync.Once
+	routes map[string]string
+}
+
+// NewResolver will create the resolver of the routes registered in the engine
+func NewResolver(e *gin.Engine) *Resolver {
+	return &Resolver{engine: e}
+}
+
+// Route returns the path template of the route serving the request, or Unmatched
+func (r *Resolver) Route(c *gin.Context) string {
+	// the routes are all registered once the first request comes in
+	r.once.Do(func() {
+		r.routes = routeTemplates(r.engine)
+	})
+	route, ok := r.routes[c.Request.Method+" "+c.HandlerName()]
+	if !ok {
+		return Unmatched
+	}
+	return route
+}
+
+// routeTemplates maps the method and the name of the handler to the path of every route
+func routeTemplates(e *gin.Engine) map[string]string {
+	routes := make(map[string]string)
+	for _, r := range e.Routes() {
+		key := r.Method + " " + r.Handler
+		if _, ok := routes[key]; !ok {
+			routes[key] = r.Path
+		}
+	}
+	return routes
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/phantomnat/go-clean-architecture/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler represents the http handler for the health checks
+type HealthHandler struct {
+	Health *health.Health
+}
+
+// NewHealthHttpHandler will initialize the /healthz and /readyz resources
+func NewHealthHttpHandler(e *gin.Engine, h *health.Health) {
+	handler := &HealthHandler{
+		Health: h,
+	}
+
+	e.GET("/healthz", handler.Liveness)
+	e.GET("/readyz", handler.Readiness)
+}
+
+// Liveness tells the process is alive, it does not depend on anything else
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+// Readiness runs the registered checks, it fails when any of them fails or the service is shutting down
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	report := h.Health.Check(ctx)
+	if !report.OK() {
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,70 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/health"
+	healthHttp "github.com/phantomnat/go-clean-architecture/health/delivery/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func get(e *gin.Engine, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLiveness(t *testing.T) {
+	h := health.NewHealth(time.Second)
+	h.Register("database", func(ctx context.Context) error { return errors.New("down") })
+	e := gin.New()
+	healthHttp.NewHealthHttpHandler(e, h)
+
+	// the process is alive even when its dependencies are not
+	rec := get(e, "/healthz")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+}
+
+func TestReadiness(t *testing.T) {
+	dbErr := error(nil)
+	h := health.NewHealth(time.Second)
+	h.Register("database", func(ctx context.Context) error { return dbErr })
+	e := gin.New()
+	healthHttp.NewHealthHttpHandler(e, h)
+
+	rec := get(e, "/readyz")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var report health.Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, health.StatusOK, report.Status)
+	assert.Equal(t, health.StatusOK, report.Checks["database"].Status)
+
+	dbErr = errors.New("connection refused")
+	rec = get(e, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, health.StatusFail, report.Status)
+	assert.Equal(t, "connection refused", report.Checks["database"].Error)
+
+	dbErr = nil
+	h.ShutDown()
+	rec = get(e, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, health.StatusShuttingDown, report.Status)
+}
@@ -0,0 +1,152 @@
+// Package health keeps the checks telling whether the service is ready to serve requests
+package health
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status of a check or of the whole service
+const (
+	StatusOK           = "ok"
+	StatusFail         = "fail"
+	StatusShuttingDown = "shutting_down"
+)
+
+// CheckFunc returns an error when the dependency is not usable.
+// It must give up once the context is done.
+type CheckFunc func(ctx context.Context) error
+
+// CheckResult is the outcome of a single check
+type CheckResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report is the outcome of every registered check
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// OK reports whether the service is ready
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Health runs the registered checks of the service's dependencies
+type Health struct {
+	timeout      time.Duration
+	shuttingDown int32
+
+	mu     sync.RWMutex
+	checks map[string]CheckFunc
+}
+
+// NewHealth will create a Health whose checks are cancelled after given timeout
+func NewHealth(timeout time.Duration) *Health {
+	return &Health{
+		timeout: timeout,
+		checks:  make(map[string]CheckFunc),
+	}
+}
+
+// Register adds the check of a dependency, it replaces the check registered with the same name
+func (h *Health) Register(name string, check CheckFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks[name] = check
+}
+
+// ShutDown marks the service as shutting down, it is not ready anymore from now on
+func (h *Health) ShutDown() {
+	atomic.StoreInt32(&h.shuttingDown, 1)
+}
+
+// IsShuttingDown reports whether ShutDown has been called
+func (h *Health) IsShuttingDown() bool {
+	return atomic.LoadInt32(&h.shuttingDown) == 1
+}
+
+// Check runs every registered check concurrently, the service is ready when all of them pass
+// and it is not shutting down
+func (h *Health) Check(ctx context.Context) Report {
+	h.mu.RLock()
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checks := make([]CheckFunc, 0, len(names))
+	for _, name := range names {
+		checks = append(checks, h.checks[name])
+	}
+	h.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check CheckFunc) {
+			defer wg.Done()
+			results[i] = run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]CheckResult, len(names)),
+	}
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	if h.IsShuttingDown() {
+		report.Status = StatusShuttingDown
+	}
+	return report
+}
+
+// run waits for the check until the context is done, a check ignoring the context can not block the report
+func run(ctx context.Context, check CheckFunc) CheckResult {
+	start := time.Now()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	res := CheckResult{
+		Status:   StatusOK,
+		Duration: time.Since(start).String(),
+	}
+	if err != nil {
+		res.Status = StatusFail
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// PingCheck checks the connectivity of the database
+func PingCheck(db *sql.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
@@ -0,0 +1,76 @@
+package health_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/health"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	h := health.NewHealth(50 * time.Millisecond)
+
+	report := h.Check(context.TODO())
+	assert.True(t, report.OK())
+	assert.Empty(t, report.Checks)
+
+	h.Register("cache", func(ctx context.Context) error { return nil })
+	report = h.Check(context.TODO())
+	assert.True(t, report.OK())
+	assert.Equal(t, health.StatusOK, report.Checks["cache"].Status)
+
+	h.Register("queue", func(ctx context.Context) error { return errors.New("connection refused") })
+	report = h.Check(context.TODO())
+	assert.False(t, report.OK())
+	assert.Equal(t, health.StatusFail, report.Status)
+	assert.Equal(t, health.StatusOK, report.Checks["cache"].Status)
+	assert.Equal(t, health.CheckResult{Status: health.StatusFail, Error: "connection refused", Duration: report.Checks["queue"].Duration},
+		report.Checks["queue"])
+}
+
+func TestCheckTimeout(t *testing.T) {
+	h := health.NewHealth(20 * time.Millisecond)
+	block := make(chan struct{})
+	defer close(block)
+	// the check ignores its context, the report must not wait for it
+	h.Register("stuck", func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+
+	start := time.Now()
+	report := h.Check(context.TODO())
+	assert.True(t, time.Since(start) < time.Second)
+	assert.False(t, report.OK())
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["stuck"].Error)
+}
+
+func TestShutDown(t *testing.T) {
+	h := health.NewHealth(time.Second)
+	h.Register("cache", func(ctx context.Context) error { return nil })
+	assert.False(t, h.IsShuttingDown())
+
+	h.ShutDown()
+	report := h.Check(context.TODO())
+	assert.False(t, report.OK())
+	assert.Equal(t, health.StatusShuttingDown, report.Status)
+	assert.Equal(t, health.StatusOK, report.Checks["cache"].Status)
+}
+
+func TestPingCheck(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	check := health.PingCheck(db)
+	assert.NoError(t, check(context.TODO()))
+
+	db.Close()
+	assert.Error(t, check(context.TODO()))
+}
@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader carries the id of the request, it is set on the response as well
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the ids given by the clients, the longer ones are replaced
+const maxRequestIDLength = 128
+
+// Middleware puts the logger of the request in its context and logs the request once it is completed.
+// The id of the request is taken from the X-Request-ID header or generated, the trace id is added when
+// the request is traced.
+func Middleware(logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+
+		fields := logrus.Fields{"request_id": id}
+		if sc := trace.SpanContextFromContext(c.Request.Context()); sc.IsValid() {
+			fields["trace_id"] = sc.TraceID().String()
+		}
+		entry := logger.WithFields(fields)
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), entry))
+
+		c.Next()
+
+		entry.WithFields(logrus.Fields{
+			"method":    c.Request.Method,
+			"path":      c.Request.URL.Path,
+			"status":    c.Writer.Status(),
+			"duration":  time.Since(start).String(),
+			"client_ip": c.ClientIP(),
+		}).Info("request completed")
+	}
+}
+
+// validRequestID tells whether the id given by the client can be logged as is
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// the request still gets served, only without a correlation id
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
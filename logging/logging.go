@@ -0,0 +1,54 @@
+// Package logging gives every request its own logger, tagged with the request id and passed along in the context,
+// so the lines the handlers, usecases and repositories log for a request can be correlated.
+package logging
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Formats of the log lines
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+type loggerKey struct{}
+
+// Configure sets the format and the level of the logger, the empty values keep the text format and the info level
+func Configure(logger *logrus.Logger, format, level string) error {
+	switch format {
+	case "", FormatText:
+		logger.SetFormatter(&logrus.TextFormatter{})
+	case FormatJSON:
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	default:
+		return fmt.Errorf("logging: unsupported format: %q", format)
+	}
+
+	lvl := logrus.InfoLevel
+	if level != "" {
+		var err error
+		lvl, err = logrus.ParseLevel(level)
+		if err != nil {
+			return err
+		}
+	}
+	logger.SetLevel(lvl)
+	return nil
+}
+
+// NewContext returns a copy of ctx carrying the logger
+func NewContext(ctx context.Context, logger *logrus.Entry) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger of the request carried by ctx, or the standard logger out of a request
+func FromContext(ctx context.Context) *logrus.Entry {
+	if logger, ok := ctx.Value(loggerKey{}).(*logrus.Entry); ok {
+		return logger
+	}
+	return logrus.NewEntry(logrus.StandardLogger())
+}
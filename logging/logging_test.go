@@ -0,0 +1,114 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/phantomnat/go-clean-architecture/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func newRouter(logger *logrus.Logger) *gin.Engine {
+	e := gin.New()
+	e.Use(logging.Middleware(logger))
+	e.GET("/articles/:id", func(c *gin.Context) {
+		logging.FromContext(c.Request.Context()).Warn("from the handler")
+		c.Status(http.StatusNoContent)
+	})
+	return e
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("generate", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		rec := httptest.NewRecorder()
+		newRouter(logger).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/articles/1", nil))
+
+		id := rec.Header().Get(logging.RequestIDHeader)
+		assert.Len(t, id, 32)
+
+		entries := hook.AllEntries()
+		require.Len(t, entries, 2)
+		assert.Equal(t, "from the handler", entries[0].Message)
+		assert.Equal(t, id, entries[0].Data["request_id"])
+
+		assert.Equal(t, "request completed", entries[1].Message)
+		assert.Equal(t, id, entries[1].Data["request_id"])
+		assert.Equal(t, http.StatusNoContent, entries[1].Data["status"])
+		assert.Equal(t, "/articles/1", entries[1].Data["path"])
+	})
+
+	t.Run("propagate", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		req := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+		req.Header.Set(logging.RequestIDHeader, "upstream-42")
+		rec := httptest.NewRecorder()
+		newRouter(logger).ServeHTTP(rec, req)
+
+		assert.Equal(t, "upstream-42", rec.Header().Get(logging.RequestIDHeader))
+		for _, entry := range hook.AllEntries() {
+			assert.Equal(t, "upstream-42", entry.Data["request_id"])
+		}
+	})
+
+	for name, id := range map[string]string{
+		"replace-spaces":   "with spaces",
+		"replace-too-long": strings.Repeat("a", 129),
+	} {
+		id := id
+		t.Run(name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			req := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+			req.Header.Set(logging.RequestIDHeader, id)
+			rec := httptest.NewRecorder()
+			newRouter(logger).ServeHTTP(rec, req)
+
+			assert.Len(t, rec.Header().Get(logging.RequestIDHeader), 32)
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, logrus.StandardLogger(), logging.FromContext(context.TODO()).Logger)
+
+	logger, hook := test.NewNullLogger()
+	ctx := logging.NewContext(context.TODO(), logger.WithField("request_id", "42"))
+	logging.FromContext(ctx).Error("failed")
+	require.Len(t, hook.AllEntries(), 1)
+	assert.Equal(t, "42", hook.LastEntry().Data["request_id"])
+}
+
+func TestConfigure(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+
+	require.NoError(t, logging.Configure(logger, logging.FormatJSON, "warn"))
+	logger.Info("hidden")
+	logger.WithField("request_id", "42").Warn("shown")
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "shown", line["msg"])
+	assert.Equal(t, "42", line["request_id"])
+
+	require.NoError(t, logging.Configure(logger, "", ""))
+	assert.Equal(t, logrus.InfoLevel, logger.GetLevel())
+
+	assert.Error(t, logging.Configure(logger, "xml", ""))
+	assert.Error(t, logging.Configure(logger, logging.FormatText, "loud"))
+}
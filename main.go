@@ -2,66 +2,202 @@ package main
 
 import (
 	"database/sql"
+	"flag"
 	"fmt"
-	"net/url"
 	"os"
+	"path/filepath"
 	"time"
 
-	"github.com/phantomnat/go-clean-architecture/article/delivery/http"
-	articleRepo "github.com/phantomnat/go-clean-architecture/article/repository/mysql"
+	apikeyMemory "github.com/phantomnat/go-clean-architecture/apikey/repository/memory"
+	apikeyMysql "github.com/phantomnat/go-clean-architecture/apikey/repository/mysql"
+	apikeySqlite "github.com/phantomnat/go-clean-architecture/apikey/repository/sqlite"
+	articleMemory "github.com/phantomnat/go-clean-architecture/article/repository/memory"
+	articleMysql "github.com/phantomnat/go-clean-architecture/article/repository/mysql"
+	articleSqlite "github.com/phantomnat/go-clean-architecture/article/repository/sqlite"
 	"github.com/phantomnat/go-clean-architecture/article/usecase"
+	authorMemory "github.com/phantomnat/go-clean-architecture/author/repository/memory"
 	authorRepo "github.com/phantomnat/go-clean-architecture/author/repository/mysql"
+	authorSqlite "github.com/phantomnat/go-clean-architecture/author/repository/sqlite"
+	authorUsecase "github.com/phantomnat/go-clean-architecture/author/usecase"
+	"github.com/phantomnat/go-clean-architecture/config"
 	"github.com/phantomnat/go-clean-architecture/config/env"
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/logging"
+	"github.com/phantomnat/go-clean-architecture/tracing"
 
-	"github.com/gin-gonic/gin"
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/mattn/go-sqlite3"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
 )
 
-var config env.Config
+// Environment variables selecting the config files, the --config and --env options take precedence over them
+const (
+	envConfigFile = "GO-CLEAN_CONFIG"
+	envConfigEnv  = "GO-CLEAN_ENV"
+)
 
-func init() {
-	config = env.NewViperConfig()
+const usage = `Usage: %s [options] <command> [arguments]
 
-	if config.GetBool("debug") {
-		fmt.Println("service run on DEBUG mode")
-	}
+Options:
+  --config file                the config file, config.yaml of the working directory by default
+  --env name                   merge the overlay of the environment over the config file, e.g. prod
+                               reads config.prod.yaml next to it
+
+Commands:
+  serve                        start the HTTP server, the default command
+  migrate up|down [n]|status   apply, revert or list the schema migrations
+  seed                         fill the database with sample authors and articles
+  article export|import        dump the articles as JSON lines or load them back
+  apikey issue|revoke|list     manage the API keys of the machine clients
+  config print                 print the effective configuration
+
+Run '%[1]s <command> -h' for the arguments of a command.
+`
+
+func printUsage() {
+	fmt.Fprintf(os.Stderr, usage, filepath.Base(os.Args[0]))
 }
 
 func main() {
-	dbHost := config.GetString("database.host")
-	dbPort := config.GetString("database.port")
-	dbUser := config.GetString("database.user")
-	dbName := config.GetString("database.name")
-	dbPass := config.GetString("database.pass")
-	connection := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", dbUser, dbPass, dbHost, dbPort, dbName)
-	val := url.Values{}
-	val.Add("parseTime", "1")
-	val.Add("loc", "Asia/Bangkok")
-	dsn := fmt.Sprintf("%s?%s", connection, val.Encode())
-	dbConn, err := sql.Open("mysql", dsn)
-	if err != nil && config.GetBool("debug") {
-		logrus.Error(err)
+	flags := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ExitOnError)
+	flags.Usage = printUsage
+	configFile := flags.String("config", os.Getenv(envConfigFile), "")
+	configEnv := flags.String("env", os.Getenv(envConfigEnv), "")
+	flags.Parse(os.Args[1:])
+
+	command, args := "serve", flags.Args()
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "help":
+		printUsage()
+		return
+	}
+
+	// the raw settings, the commands run with the AppConfig loaded from them
+	settings, err := env.NewViperConfig(env.Options{File: *configFile, Env: *configEnv})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	cfg, err := config.Load(settings)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := logging.Configure(logrus.StandardLogger(), cfg.Logging.Format, cfg.Logging.Level); err != nil {
+		logrus.Fatal(err)
+	}
+
+	switch command {
+	case "serve":
+		runServe(settings, cfg, args)
+	case "migrate":
+		runMigrate(cfg, args)
+	case "seed":
+		runSeed(cfg, args)
+	case "article":
+		runArticle(cfg, args)
+	case "apikey":
+		runAPIKey(cfg, args)
+	case "config":
+		runConfig(cfg, args)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", command)
+		printUsage()
+		os.Exit(2)
+	}
+}
+
+// openDB opens and checks the connection of the sql database, its queries are traced when traced is set
+func openDB(db config.DatabaseConfig, traced bool) *sql.DB {
+	var (
+		dbConn *sql.DB
+		err    error
+	)
+	if traced {
+		// the tracer provider is registered by serve, the other commands trace nothing
+		dbConn, err = tracing.OpenDB(db.Driver, db.DSN(), otel.GetTracerProvider())
+	} else {
+		dbConn, err = sql.Open(db.Driver, db.DSN())
+	}
+	if err != nil {
+		logrus.Fatal(err)
 	}
 	err = dbConn.Ping()
 	if err != nil {
 		logrus.Fatal(err)
-		os.Exit(1)
 	}
-	defer func() {
-		err = dbConn.Close()
-		if err != nil {
-			logrus.Fatal(err)
-		}
-	}()
+	if db.Driver == config.DriverSqlite {
+		// sqlite only allows a single writer, serialize the access instead of failing with SQLITE_BUSY
+		dbConn.SetMaxOpenConns(1)
+	}
+	return dbConn
+}
+
+func closeDB(dbConn *sql.DB) {
+	err := dbConn.Close()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+}
 
-	router := gin.Default()
-	authoreRepo := authorRepo.NewMysqlAuthorRepository(dbConn)
-	articleRepo := articleRepo.NewMysqlArticleRepository(dbConn)
-	timeoutContext := time.Second * 2
-	au := usecase.NewArticleUseCase(articleRepo, authoreRepo, timeoutContext)
+// storage holds the repositories of the configured database driver
+type storage struct {
+	dbConn      *sql.DB
+	authorRepo  domain.AuthorRepository
+	articleRepo domain.ArticleRepository
+	apiKeyRepo  domain.APIKeyRepository
+}
 
-	http.NewArticleHttpHandler(router, au)
+// openStorage creates the repositories of the configured driver, the pending migrations are applied
+// when database.auto_migrate is enabled
+func openStorage(db config.DatabaseConfig, traced bool) storage {
+	switch db.Driver {
+	case config.DriverMemory:
+		return storage{
+			authorRepo:  authorMemory.NewMemoryAuthorRepository(),
+			articleRepo: articleMemory.NewMemoryArticleRepository(),
+			apiKeyRepo:  apikeyMemory.NewMemoryAPIKeyRepository(),
+		}
+	case config.DriverSqlite:
+		dbConn := openDB(db, traced)
+		migrateUp(db, dbConn)
+		return storage{
+			dbConn:      dbConn,
+			authorRepo:  authorSqlite.NewSqliteAuthorRepository(dbConn),
+			articleRepo: articleSqlite.NewSqliteArticleRepository(dbConn),
+			apiKeyRepo:  apikeySqlite.NewSqliteAPIKeyRepository(dbConn),
+		}
+	default:
+		dbConn := openDB(db, traced)
+		migrateUp(db, dbConn)
+		return storage{
+			dbConn:      dbConn,
+			authorRepo:  authorRepo.NewMysqlAuthorRepository(dbConn),
+			articleRepo: articleMysql.NewMysqlArticleRepository(dbConn),
+			apiKeyRepo:  apikeyMysql.NewMysqlAPIKeyRepository(dbConn),
+		}
+	}
+}
 
-	router.Run()
+func (s storage) close() {
+	if s.dbConn != nil {
+		closeDB(s.dbConn)
+	}
+}
+
+// newUsecases creates the usecases of the storage, every call is bounded by timeoutContext. With authorize, the
+// callers are authorized by the policies of the roles, otherwise they are trusted with everything, e.g. the commands.
+func newUsecases(s storage, authorize bool, timeoutContext time.Duration) (domain.ArticleUsecase, domain.AuthorUsecase) {
+	articlePolicy, authorPolicy := usecase.NewUnrestrictedArticlePolicy(), authorUsecase.NewUnrestrictedAuthorPolicy()
+	if authorize {
+		articlePolicy, authorPolicy = usecase.NewArticlePolicy(), authorUsecase.NewAuthorPolicy()
+	}
+	au := usecase.NewArticleUseCase(s.articleRepo, s.authorRepo, articlePolicy, timeoutContext)
+	auu := authorUsecase.NewAuthorUseCase(s.authorRepo, s.articleRepo, authorPolicy, timeoutContext)
+	return au, auu
 }
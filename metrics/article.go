@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// observe records the duration of a call since start along with its result
+func observe(h *prometheus.HistogramVec, name, method string, start time.Time, err error) {
+	h.WithLabelValues(name, method, resultLabel(err)).Observe(time.Since(start).Seconds())
+}
+
+type articleUsecase struct {
+	next    domain.ArticleUsecase
+	metrics *Metrics
+}
+
+// NewArticleUsecase will decorate the usecase to time every call
+func NewArticleUsecase(next domain.ArticleUsecase, m *Metrics) domain.ArticleUsecase {
+	return &articleUsecase{
+		next:    next,
+		metrics: m,
+	}
+}
+
+func (a *articleUsecase) observe(method string, start time.Time, err error) {
+	observe(a.metrics.usecaseDuration, "article", method, start, err)
+}
+
+func (a *articleUsecase) Fetch(ctx context.Context, filter domain.ArticleFilter, cursor string, num int64,
+	direction domain.Direction, order domain.SortOrder) (res []domain.Article, nextCursor string, prevCursor string, err error) {
+	defer func(start time.Time) { a.observe("Fetch", start, err) }(time.Now())
+	return a.next.Fetch(ctx, filter, cursor, num, direction, order)
+}
+
+func (a *articleUsecase) GetByID(ctx context.Context, id int64) (res domain.Article, err error) {
+	defer func(start time.Time) { a.observe("GetByID", start, err) }(time.Now())
+	return a.next.GetByID(ctx, id)
+}
+
+func (a *articleUsecase) Update(ctx context.Context, ar *domain.Article) (err error) {
+	defer func(start time.Time) { a.observe("Update", start, err) }(time.Now())
+	return a.next.Update(ctx, ar)
+}
+
+func (a *articleUsecase) GetByTitle(ctx context.Context, title string) (res domain.Article, err error) {
+	defer func(start time.Time) { a.observe("GetByTitle", start, err) }(time.Now())
+	return a.next.GetByTitle(ctx, title)
+}
+
+func (a *articleUsecase) Search(ctx context.Context, query string, cursor string, num int64) (
+	res []domain.ArticleSearchResult, nextCursor string, err error) {
+	defer func(start time.Time) { a.observe("Search", start, err) }(time.Now())
+	return a.next.Search(ctx, query, cursor, num)
+}
+
+func (a *articleUsecase) Store(ctx context.Context, ar *domain.Article) (err error) {
+	defer func(start time.Time) { a.observe("Store", start, err) }(time.Now())
+	return a.next.Store(ctx, ar)
+}
+
+func (a *articleUsecase) Delete(ctx context.Context, id int64) (err error) {
+	defer func(start time.Time) { a.observe("Delete", start, err) }(time.Now())
+	return a.next.Delete(ctx, id)
+}
+
+type articleRepository struct {
+	next    domain.ArticleRepository
+	metrics *Metrics
+}
+
+// NewArticleRepository will decorate the repository to time every call
+func NewArticleRepository(next domain.ArticleRepository, m *Metrics) domain.ArticleRepository {
+	return &articleRepository{
+		next:    next,
+		metrics: m,
+	}
+}
+
+func (a *articleRepository) observe(method string, start time.Time, err error) {
+	observe(a.metrics.repositoryDuration, "article", method, start, err)
+}
+
+func (a *articleRepository) Fetch(ctx context.Context, filter domain.ArticleFilter, cursor string, num int64,
+	direction domain.Direction, order domain.SortOrder) (res []domain.Article, nextCursor string, prevCursor string, err error) {
+	defer func(start time.Time) { a.observe("Fetch", start, err) }(time.Now())
+	return a.next.Fetch(ctx, filter, cursor, num, direction, order)
+}
+
+func (a *articleRepository) GetByID(ctx context.Context, id int64) (res domain.Article, err error) {
+	defer func(start time.Time) { a.observe("GetByID", start, err) }(time.Now())
+	return a.next.GetByID(ctx, id)
+}
+
+func (a *articleRepository) GetByTitle(ctx context.Context, title string) (res domain.Article, err error) {
+	defer func(start time.Time) { a.observe("GetByTitle", start, err) }(time.Now())
+	return a.next.GetByTitle(ctx, title)
+}
+
+func (a *articleRepository) Search(ctx context.Context, query string, cursor string, num int64) (
+	res []domain.ArticleSearchResult, nextCursor string, err error) {
+	defer func(start time.Time) { a.observe("Search", start, err) }(time.Now())
+	return a.next.Search(ctx, query, cursor, num)
+}
+
+func (a *articleRepository) Update(ctx context.Context, ar *domain.Article) (err error) {
+	defer func(start time.Time) { a.observe("Update", start, err) }(time.Now())
+	return a.next.Update(ctx, ar)
+}
+
+func (a *articleRepository) Store(ctx context.Context, ar *domain.Article) (err error) {
+	defer func(start time.Time) { a.observe("Store", start, err) }(time.Now())
+	return a.next.Store(ctx, ar)
+}
+
+func (a *articleRepository) Delete(ctx context.Context, id int64) (err error) {
+	defer func(start time.Time) { a.observe("Delete", start, err) }(time.Now())
+	return a.next.Delete(ctx, id)
+}
@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/ginroute"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware counts and times the requests of the engine's routes.
+// The route label is the path template, e.g. /articles/:id, so the raw paths do not blow up the cardinality.
+func (m *Metrics) Middleware(e *gin.Engine) gin.HandlerFunc {
+	routes := ginroute.NewResolver(e)
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := routes.Route(c)
+		status := strconv.Itoa(c.Writer.Status())
+		m.httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		m.httpDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,96 @@
+// Package metrics exposes the Prometheus metrics of the HTTP server, the usecases and the repositories
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Result label of the usecase and repository calls
+const (
+	ResultOK           = "ok"
+	ResultNotFound     = "not_found"
+	ResultAlreadyExist = "already_exist"
+	ResultBadParam     = "bad_param"
+	ResultForbidden    = "forbidden"
+	ResultError        = "error"
+)
+
+// Metrics holds the collectors of the service, every one of them is registered in Registry
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	httpRequests       *prometheus.CounterVec
+	httpDuration       *prometheus.HistogramVec
+	usecaseDuration    *prometheus.HistogramVec
+	repositoryDuration *prometheus.HistogramVec
+}
+
+// NewMetrics will create the metrics along with the Go runtime and process collectors
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Number of HTTP requests by route and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Duration of the HTTP requests by route and status.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		usecaseDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "usecase_call_duration_seconds",
+			Help:    "Duration of the usecase calls by method and result.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"usecase", "method", "result"}),
+		repositoryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "repository_call_duration_seconds",
+			Help:    "Duration of the repository calls by method and result.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"repository", "method", "result"}),
+	}
+
+	m.Registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.usecaseDuration,
+		m.repositoryDuration,
+	)
+	return m
+}
+
+// Handler serves the registered metrics in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDB exposes the connection pool statistics of the database
+func (m *Metrics) RegisterDB(name string, db *sql.DB) error {
+	return m.Registry.Register(NewDBStatsCollector(name, db))
+}
+
+// resultLabel maps the error of a call to a label with a bounded set of values
+func resultLabel(err error) string {
+	switch err {
+	case nil:
+		return ResultOK
+	case domain.ErrNotFound:
+		return ResultNotFound
+	case domain.ErrAlreadyExist:
+		return ResultAlreadyExist
+	case domain.ErrBadParamInput:
+		return ResultBadParam
+	case domain.ErrForbidden:
+		return ResultForbidden
+	default:
+		return ResultError
+	}
+}
@@ -0,0 +1,133 @@
+package metrics_test
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/domain/mocks"
+	"github.com/phantomnat/go-clean-architecture/metrics"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// sampleCount returns the number of observations of the histogram with given labels
+func sampleCount(t *testing.T, m *metrics.Metrics, name string, labels map[string]string) uint64 {
+	families, err := m.Registry.Gather()
+	require.NoError(t, err)
+
+	for _, mf := range families {
+		if mf.GetName() != name {
+			continue
+		}
+	metric:
+		for _, metric := range mf.GetMetric() {
+			for _, l := range metric.GetLabel() {
+				if v, ok := labels[l.GetName()]; ok && v != l.GetValue() {
+					continue metric
+				}
+			}
+			return metric.GetHistogram().GetSampleCount()
+		}
+	}
+	return 0
+}
+
+func TestMiddleware(t *testing.T) {
+	m := metrics.NewMetrics()
+	e := gin.New()
+	e.Use(m.Middleware(e))
+	e.GET("/articles/:id", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	e.GET("/metrics", gin.WrapH(m.Handler()))
+
+	for _, path := range []string{"/articles/1", "/articles/2", "/unknown"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	expected := `
+# HELP http_requests_total Number of HTTP requests by route and status.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",route="/articles/:id",status="204"} 2
+http_requests_total{method="GET",route="unmatched",status="404"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(m.Registry, strings.NewReader(expected), "http_requests_total"))
+	assert.Equal(t, uint64(2), sampleCount(t, m, "http_request_duration_seconds", map[string]string{"route": "/articles/:id"}))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body, _ := ioutil.ReadAll(rec.Body)
+	assert.Contains(t, string(body), `http_requests_total{method="GET",route="/articles/:id",status="204"} 2`)
+	assert.Contains(t, string(body), "go_goroutines")
+}
+
+func TestArticleUsecase(t *testing.T) {
+	m := metrics.NewMetrics()
+	mockUCase := new(mocks.ArticleUsecase)
+	mockUCase.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1}, nil).Once()
+	mockUCase.On("GetByID", mock.Anything, int64(2)).Return(domain.Article{}, domain.ErrNotFound).Once()
+
+	u := metrics.NewArticleUsecase(mockUCase, m)
+	ar, err := u.GetByID(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), ar.ID)
+	_, err = u.GetByID(context.TODO(), 2)
+	assert.Equal(t, domain.ErrNotFound, err)
+
+	labels := map[string]string{"usecase": "article", "method": "GetByID", "result": metrics.ResultOK}
+	assert.Equal(t, uint64(1), sampleCount(t, m, "usecase_call_duration_seconds", labels))
+	labels["result"] = metrics.ResultNotFound
+	assert.Equal(t, uint64(1), sampleCount(t, m, "usecase_call_duration_seconds", labels))
+	mockUCase.AssertExpectations(t)
+}
+
+func TestArticleRepository(t *testing.T) {
+	m := metrics.NewMetrics()
+	mockRepo := new(mocks.ArticleRepository)
+	mockRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(domain.ErrInternalServer).Once()
+
+	r := metrics.NewArticleRepository(mockRepo, m)
+	assert.Equal(t, domain.ErrInternalServer, r.Store(context.TODO(), &domain.Article{}))
+
+	labels := map[string]string{"repository": "article", "method": "Store", "result": metrics.ResultError}
+	assert.Equal(t, uint64(1), sampleCount(t, m, "repository_call_duration_seconds", labels))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRegisterDB(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(3)
+	require.NoError(t, db.Ping())
+
+	m := metrics.NewMetrics()
+	require.NoError(t, m.RegisterDB("main", db))
+
+	expected := `
+# HELP db_max_open_connections Maximum number of open connections to the database.
+# TYPE db_max_open_connections gauge
+db_max_open_connections{db="main"} 3
+# HELP db_open_connections Number of established connections, in use and idle.
+# TYPE db_open_connections gauge
+db_open_connections{db="main"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(m.Registry, strings.NewReader(expected),
+		"db_max_open_connections", "db_open_connections"))
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/config"
+	"github.com/phantomnat/go-clean-architecture/migration"
+
+	"github.com/sirupsen/logrus"
+)
+
+// migrateUp applies the pending migrations when database.auto_migrate is enabled
+func migrateUp(db config.DatabaseConfig, dbConn *sql.DB) {
+	if !db.AutoMigrate {
+		return
+	}
+
+	m, err := migration.NewMigrator(dbConn, db.Driver)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	applied, err := m.Up(context.Background())
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	for _, mg := range applied {
+		logrus.Infof("applied migration %d %s", mg.Version, mg.Name)
+	}
+}
+
+// runMigrate implements `migrate up|down [steps]|status`
+func runMigrate(cfg config.AppConfig, args []string) {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: migrate up|down [steps]|status")
+	}
+	fs.Parse(args)
+	args = fs.Args()
+	if len(args) == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if cfg.Database.Driver == config.DriverMemory {
+		logrus.Fatal("the memory driver has no schema to migrate")
+	}
+
+	dbConn := openDB(cfg.Database, false)
+	defer closeDB(dbConn)
+
+	m, err := migration.NewMigrator(dbConn, cfg.Database.Driver)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, mg := range applied {
+			fmt.Printf("applied %d %s\n", mg.Version, mg.Name)
+		}
+		if err != nil {
+			logrus.Fatal(err)
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				logrus.Fatalf("invalid number of steps: %s", args[1])
+			}
+		}
+		reverted, err := m.Down(ctx, steps)
+		for _, mg := range reverted {
+			fmt.Printf("reverted %d %s\n", mg.Version, mg.Name)
+		}
+		if err != nil {
+			logrus.Fatal(err)
+		}
+	case "status":
+		status, err := m.Status(ctx)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range status {
+			appliedAt := "pending"
+			if s.Applied {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		w.Flush()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown migrate command: %s\n", args[0])
+		fs.Usage()
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,330 @@
+// Package migration keeps the database schema up to date with ordered, versioned migrations.
+// The applied versions are recorded in the schema_version table along with the checksum of
+// their statements, so a migration edited after it was applied is detected instead of ignored.
+// The migrators of a database take a lock, the instances started together apply every migration once.
+package migration
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const versionTable = `CREATE TABLE IF NOT EXISTS schema_version (
+	version BIGINT NOT NULL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	checksum CHAR(64) NOT NULL,
+	applied_at DATETIME NOT NULL
+)`
+
+var (
+	// ErrUnsupportedDialect is returned when there are no migrations for the database driver
+	ErrUnsupportedDialect = errors.New("migration: unsupported database driver")
+)
+
+// ChecksumMismatchError is returned when an applied migration has been modified afterward
+type ChecksumMismatchError struct {
+	Version int64
+	Name    string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("migration: checksum mismatch of version %d (%s), applied migrations must not be edited", e.Version, e.Name)
+}
+
+// UnknownVersionError is returned when the database has a version this binary does not know about,
+// usually because it has been migrated by a newer release
+type UnknownVersionError struct {
+	Version int64
+}
+
+func (e *UnknownVersionError) Error() string {
+	return fmt.Sprintf("migration: unknown applied version %d", e.Version)
+}
+
+// Migration is a single versioned change of the schema.
+// Up and Down hold one SQL statement per item, the MySQL driver does not run several statements at once.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      []string
+	Down    []string
+}
+
+// Checksum returns the hex encoded sha256 of the migration's statements
+func (m Migration) Checksum() string {
+	h := sha256.New()
+	for _, stmt := range m.Up {
+		h.Write([]byte(stmt))
+		h.Write([]byte{0})
+	}
+	h.Write([]byte{0})
+	for _, stmt := range m.Down {
+		h.Write([]byte(stmt))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Status tells whether a migration has been applied
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// dialect is how a database serializes its migrators. The lock is held by the connection of the migrator,
+// every migration runs on it between begin and commit, or rollback when it fails.
+type dialect struct {
+	lock     func(ctx context.Context, conn *sql.Conn) error
+	unlock   func(ctx context.Context, conn *sql.Conn) error
+	begin    string
+	commit   string
+	rollback []string
+}
+
+// exec returns the lock function running the statement on the connection
+func exec(stmt string) func(ctx context.Context, conn *sql.Conn) error {
+	return func(ctx context.Context, conn *sql.Conn) error {
+		_, err := conn.ExecContext(ctx, stmt)
+		return err
+	}
+}
+
+// Migrator applies and reverts the migrations of one database
+type Migrator struct {
+	db         *sql.DB
+	dialect    dialect
+	migrations []Migration
+}
+
+// NewMigrator will create a migrator with the migrations of given database driver, "mysql" or "sqlite3"
+func NewMigrator(db *sql.DB, driver string) (*Migrator, error) {
+	switch driver {
+	case "mysql":
+		return NewMigratorWithMigrations(db, driver, mysqlMigrations)
+	case "sqlite3":
+		return NewMigratorWithMigrations(db, driver, sqliteMigrations)
+	default:
+		return nil, ErrUnsupportedDialect
+	}
+}
+
+// NewMigratorWithMigrations will create a migrator of the database driver with given migrations,
+// the versions must be unique
+func NewMigratorWithMigrations(db *sql.DB, driver string, migrations []Migration) (*Migrator, error) {
+	var d dialect
+	switch driver {
+	case "mysql":
+		d = mysqlDialect
+	case "sqlite3":
+		d = sqliteDialect
+	default:
+		return nil, ErrUnsupportedDialect
+	}
+
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	for i, m := range sorted {
+		if m.Version <= 0 {
+			return nil, fmt.Errorf("migration: invalid version %d", m.Version)
+		}
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("migration: duplicated version %d", m.Version)
+		}
+	}
+
+	return &Migrator{
+		db:         db,
+		dialect:    d,
+		migrations: sorted,
+	}, nil
+}
+
+type appliedVersion struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+// querier is the database or the connection the migrator reads the applied versions with
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// applied reads the schema_version table and checks the recorded checksums against the known migrations
+func (m *Migrator) applied(ctx context.Context, q querier) (map[int64]appliedVersion, error) {
+	if _, err := q.ExecContext(ctx, versionTable); err != nil {
+		return nil, err
+	}
+
+	rows, err := q.QueryContext(ctx, `SELECT version, checksum, applied_at FROM schema_version`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make(map[int64]appliedVersion)
+	for rows.Next() {
+		var (
+			version int64
+			av      appliedVersion
+		)
+		if err := rows.Scan(&version, &av.checksum, &av.appliedAt); err != nil {
+			return nil, err
+		}
+		res[version] = av
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	known := make(map[int64]Migration, len(m.migrations))
+	for _, mg := range m.migrations {
+		known[mg.Version] = mg
+	}
+	for version, av := range res {
+		mg, ok := known[version]
+		if !ok {
+			return nil, &UnknownVersionError{Version: version}
+		}
+		if mg.Checksum() != av.checksum {
+			return nil, &ChecksumMismatchError{Version: version, Name: mg.Name}
+		}
+	}
+
+	return res, nil
+}
+
+// Status returns every known migration in order, along with whether it has been applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Status, 0, len(m.migrations))
+	for _, mg := range m.migrations {
+		av, ok := applied[mg.Version]
+		res = append(res, Status{
+			Migration: mg,
+			Applied:   ok,
+			AppliedAt: av.appliedAt,
+		})
+	}
+	return res, nil
+}
+
+// Up applies every pending migration in order and returns the applied ones
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	res := make([]Migration, 0)
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mg := range m.migrations {
+			if _, ok := applied[mg.Version]; ok {
+				continue
+			}
+			if err := m.run(ctx, conn, mg, mg.Up, true); err != nil {
+				return fmt.Errorf("migration: up %d (%s): %v", mg.Version, mg.Name, err)
+			}
+			res = append(res, mg)
+		}
+		return nil
+	})
+	return res, err
+}
+
+// Down reverts the last applied migrations, at most steps of them, and returns the reverted ones
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	res := make([]Migration, 0)
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(res) < steps; i-- {
+			mg := m.migrations[i]
+			if _, ok := applied[mg.Version]; !ok {
+				continue
+			}
+			if err := m.run(ctx, conn, mg, mg.Down, false); err != nil {
+				return fmt.Errorf("migration: down %d (%s): %v", mg.Version, mg.Name, err)
+			}
+			res = append(res, mg)
+		}
+		return nil
+	})
+	return res, err
+}
+
+// locked runs fn with a connection holding the lock of the migrations, the other migrators of the database
+// wait until it is done, then find the versions it applied
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err = m.dialect.lock(ctx, conn); err != nil {
+		return fmt.Errorf("migration: lock: %v", err)
+	}
+	err = fn(conn)
+	// released even when the context is done, the lock must not outlive the migrator
+	if uerr := m.dialect.unlock(context.Background(), conn); uerr != nil && err == nil {
+		err = fmt.Errorf("migration: unlock: %v", uerr)
+	}
+	return err
+}
+
+// run executes the statements and records the version in a single transaction.
+// MySQL commits the DDL statements implicitly, a failed migration there may be left half applied.
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, mg Migration, statements []string, up bool) (err error) {
+	if _, err = conn.ExecContext(ctx, m.dialect.begin); err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			for _, stmt := range m.dialect.rollback {
+				conn.ExecContext(context.Background(), stmt)
+			}
+		}
+	}()
+
+	for _, stmt := range statements {
+		if strings.TrimSpace(stmt) == "" {
+			continue
+		}
+		if _, err = conn.ExecContext(ctx, stmt); err != nil {
+			return
+		}
+	}
+
+	if up {
+		_, err = conn.ExecContext(ctx, `INSERT INTO schema_version (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)`,
+			mg.Version, mg.Name, mg.Checksum(), time.Now().UTC())
+	} else {
+		_, err = conn.ExecContext(ctx, `DELETE FROM schema_version WHERE version = ?`, mg.Version)
+	}
+	if err != nil {
+		return
+	}
+
+	_, err = conn.ExecContext(ctx, m.dialect.commit)
+	return
+}
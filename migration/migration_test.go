@@ -0,0 +1,207 @@
+package migration_test
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	apikeySqlite "github.com/phantomnat/go-clean-architecture/apikey/repository/sqlite"
+	articleSqlite "github.com/phantomnat/go-clean-architecture/article/repository/sqlite"
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/migration"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	// every connection of an in-memory database is a separate database
+	db.SetMaxOpenConns(1)
+	return db
+}
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	var n int
+	err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, name).Scan(&n)
+	require.NoError(t, err)
+	return n > 0
+}
+
+func TestUpAndDown(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+
+	m, err := migration.NewMigrator(db, "sqlite3")
+	require.NoError(t, err)
+
+	applied, err := m.Up(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, applied, 3)
+	assert.Equal(t, int64(1), applied[0].Version)
+	assert.Equal(t, int64(2), applied[1].Version)
+	assert.Equal(t, int64(3), applied[2].Version)
+	assert.True(t, tableExists(t, db, "author"))
+	assert.True(t, tableExists(t, db, "article"))
+	assert.True(t, tableExists(t, db, "api_key"))
+
+	// the migrated schema is the one the repositories expect
+	ar := domain.Article{Title: "title", Content: "content"}
+	require.NoError(t, articleSqlite.NewSqliteArticleRepository(db).Store(context.TODO(), &ar))
+	k := domain.APIKey{Name: "importer", Prefix: "gca_abcdefgh", Hash: "hash", Scopes: []domain.APIKeyScope{domain.ScopeArticlesRead}}
+	require.NoError(t, apikeySqlite.NewSqliteAPIKeyRepository(db).Store(context.TODO(), &k))
+
+	applied, err = m.Up(context.TODO())
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	status, err := m.Status(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, status, 3)
+	for _, s := range status {
+		assert.True(t, s.Applied)
+		assert.False(t, s.AppliedAt.IsZero())
+	}
+
+	reverted, err := m.Down(context.TODO(), 1)
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, int64(3), reverted[0].Version)
+	assert.False(t, tableExists(t, db, "api_key"))
+	assert.True(t, tableExists(t, db, "article"))
+
+	status, err = m.Status(context.TODO())
+	require.NoError(t, err)
+	assert.True(t, status[1].Applied)
+	assert.False(t, status[2].Applied)
+
+	reverted, err = m.Down(context.TODO(), 10)
+	require.NoError(t, err)
+	assert.Len(t, reverted, 2)
+	assert.False(t, tableExists(t, db, "article"))
+	assert.False(t, tableExists(t, db, "author"))
+}
+
+func TestChecksumMismatch(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+
+	m, err := migration.NewMigrator(db, "sqlite3")
+	require.NoError(t, err)
+	_, err = m.Up(context.TODO())
+	require.NoError(t, err)
+
+	_, err = db.Exec(`UPDATE schema_version SET checksum = 'edited' WHERE version = 2`)
+	require.NoError(t, err)
+
+	_, err = m.Up(context.TODO())
+	require.Error(t, err)
+	mismatch, ok := err.(*migration.ChecksumMismatchError)
+	require.True(t, ok, "unexpected error %v", err)
+	assert.Equal(t, int64(2), mismatch.Version)
+
+	_, err = m.Status(context.TODO())
+	assert.Error(t, err)
+}
+
+func TestUnknownVersion(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+
+	newer, err := migration.NewMigratorWithMigrations(db, "sqlite3", []migration.Migration{
+		{Version: 1, Name: "one", Up: []string{"CREATE TABLE one (id INTEGER)"}, Down: []string{"DROP TABLE one"}},
+		{Version: 2, Name: "two", Up: []string{"CREATE TABLE two (id INTEGER)"}, Down: []string{"DROP TABLE two"}},
+	})
+	require.NoError(t, err)
+	_, err = newer.Up(context.TODO())
+	require.NoError(t, err)
+
+	older, err := migration.NewMigratorWithMigrations(db, "sqlite3", []migration.Migration{
+		{Version: 1, Name: "one", Up: []string{"CREATE TABLE one (id INTEGER)"}, Down: []string{"DROP TABLE one"}},
+	})
+	require.NoError(t, err)
+	_, err = older.Up(context.TODO())
+	_, ok := err.(*migration.UnknownVersionError)
+	assert.True(t, ok, "unexpected error %v", err)
+}
+
+func TestFailedMigrationIsRolledBack(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+
+	m, err := migration.NewMigratorWithMigrations(db, "sqlite3", []migration.Migration{
+		{Version: 1, Name: "one", Up: []string{"CREATE TABLE one (id INTEGER)"}, Down: []string{"DROP TABLE one"}},
+		{Version: 2, Name: "broken", Up: []string{"CREATE TABLE two (id INTEGER)", "NOT SQL"}, Down: []string{"DROP TABLE two"}},
+	})
+	require.NoError(t, err)
+
+	applied, err := m.Up(context.TODO())
+	assert.Error(t, err)
+	assert.Len(t, applied, 1)
+	assert.True(t, tableExists(t, db, "one"))
+	assert.False(t, tableExists(t, db, "two"))
+
+	status, err := m.Status(context.TODO())
+	require.NoError(t, err)
+	assert.True(t, status[0].Applied)
+	assert.False(t, status[1].Applied)
+}
+
+func TestConcurrentUp(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migration")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	dsn := "file:" + filepath.Join(dir, "test.db") + "?_busy_timeout=5000"
+
+	// the instances started together each have their own connections to the database
+	migrators := make([]*migration.Migrator, 3)
+	for i := range migrators {
+		db, err := sql.Open("sqlite3", dsn)
+		require.NoError(t, err)
+		defer db.Close()
+		migrators[i], err = migration.NewMigrator(db, "sqlite3")
+		require.NoError(t, err)
+	}
+
+	var wg sync.WaitGroup
+	applied := make([][]migration.Migration, len(migrators))
+	errs := make([]error, len(migrators))
+	for i, m := range migrators {
+		wg.Add(1)
+		go func(i int, m *migration.Migrator) {
+			defer wg.Done()
+			applied[i], errs[i] = m.Up(context.TODO())
+		}(i, m)
+	}
+	wg.Wait()
+
+	total := 0
+	for i := range migrators {
+		assert.NoError(t, errs[i])
+		total += len(applied[i])
+	}
+	assert.Equal(t, 3, total)
+
+	status, err := migrators[0].Status(context.TODO())
+	require.NoError(t, err)
+	for _, s := range status {
+		assert.True(t, s.Applied)
+	}
+}
+
+func TestNewMigrator(t *testing.T) {
+	_, err := migration.NewMigrator(nil, "postgres")
+	assert.Equal(t, migration.ErrUnsupportedDialect, err)
+
+	_, err = migration.NewMigratorWithMigrations(nil, "sqlite3", []migration.Migration{{Version: 1}, {Version: 1}})
+	assert.Error(t, err)
+
+	_, err = migration.NewMigrator(nil, "mysql")
+	assert.NoError(t, err)
+}
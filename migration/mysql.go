@@ -0,0 +1,103 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// mysqlLockTimeout is how long, in seconds, a migrator waits for the one holding the lock
+const mysqlLockTimeout = 300
+
+// mysqlDialect takes a named lock of the server, the DDL statements cannot be serialized by a transaction
+var mysqlDialect = dialect{
+	lock: func(ctx context.Context, conn *sql.Conn) error {
+		var acquired sql.NullInt64
+		err := conn.QueryRowContext(ctx, `SELECT GET_LOCK('schema_version', ?)`, mysqlLockTimeout).Scan(&acquired)
+		if err != nil {
+			return err
+		}
+		if acquired.Int64 != 1 {
+			return fmt.Errorf("timed out after %ds, another migrator holds the lock", mysqlLockTimeout)
+		}
+		return nil
+	},
+	unlock:   exec(`DO RELEASE_LOCK('schema_version')`),
+	begin:    `START TRANSACTION`,
+	commit:   `COMMIT`,
+	rollback: []string{`ROLLBACK`},
+}
+
+// mysqlMigrations must only be appended to, an applied migration is never edited
+var mysqlMigrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create_author",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS author (
+	id BIGINT NOT NULL AUTO_INCREMENT,
+	name VARCHAR(255) NOT NULL,
+	created_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL,
+	PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS author`,
+		},
+	},
+	{
+		Version: 2,
+		Name:    "create_article",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS article (
+	id BIGINT NOT NULL AUTO_INCREMENT,
+	title VARCHAR(255) NOT NULL,
+	content TEXT NOT NULL,
+	author_id BIGINT NOT NULL DEFAULT 0,
+	updated_at DATETIME NOT NULL,
+	created_at DATETIME NOT NULL,
+	PRIMARY KEY (id),
+	KEY idx_article_created_at (created_at, id),
+	KEY idx_article_updated_at (updated_at, id),
+	KEY idx_article_author_id (author_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS article`,
+		},
+	},
+	{
+		Version: 3,
+		Name:    "add_article_fulltext",
+		Up: []string{
+			`ALTER TABLE article ADD FULLTEXT INDEX ft_article_title_content (title, content)`,
+		},
+		Down: []string{
+			`ALTER TABLE article DROP INDEX ft_article_title_content`,
+		},
+	},
+	{
+		Version: 4,
+		Name:    "create_api_key",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS api_key (
+	id BIGINT NOT NULL AUTO_INCREMENT,
+	name VARCHAR(255) NOT NULL,
+	prefix VARCHAR(16) NOT NULL,
+	hash CHAR(64) NOT NULL,
+	scopes VARCHAR(255) NOT NULL,
+	author_id BIGINT NOT NULL DEFAULT 0,
+	expires_at DATETIME NULL,
+	last_used_at DATETIME NULL,
+	revoked_at DATETIME NULL,
+	created_at DATETIME NOT NULL,
+	PRIMARY KEY (id),
+	UNIQUE KEY uniq_api_key_hash (hash)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS api_key`,
+		},
+	},
+}
@@ -0,0 +1,71 @@
+package migration
+
+// sqliteDialect runs the migrations in an immediate transaction, it keeps the other writers waiting until
+// it is committed. Every migration is a savepoint of it, a failed one is rolled back alone.
+var sqliteDialect = dialect{
+	lock:     exec(`BEGIN IMMEDIATE`),
+	unlock:   exec(`COMMIT`),
+	begin:    `SAVEPOINT migration`,
+	commit:   `RELEASE migration`,
+	rollback: []string{`ROLLBACK TO migration`, `RELEASE migration`},
+}
+
+// sqliteMigrations must only be appended to, an applied migration is never edited
+var sqliteMigrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create_author",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS author (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name VARCHAR(255) NOT NULL,
+	created_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL
+)`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS author`,
+		},
+	},
+	{
+		Version: 2,
+		Name:    "create_article",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS article (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	title VARCHAR(255) NOT NULL,
+	content TEXT NOT NULL,
+	author_id INTEGER NOT NULL DEFAULT 0,
+	updated_at DATETIME NOT NULL,
+	created_at DATETIME NOT NULL
+)`,
+			`CREATE INDEX IF NOT EXISTS idx_article_created_at ON article (created_at, id)`,
+			`CREATE INDEX IF NOT EXISTS idx_article_updated_at ON article (updated_at, id)`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS article`,
+		},
+	},
+	{
+		Version: 3,
+		Name:    "create_api_key",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS api_key (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name VARCHAR(255) NOT NULL,
+	prefix VARCHAR(16) NOT NULL,
+	hash CHAR(64) NOT NULL,
+	scopes VARCHAR(255) NOT NULL,
+	author_id INTEGER NOT NULL DEFAULT 0,
+	expires_at DATETIME NULL,
+	last_used_at DATETIME NULL,
+	revoked_at DATETIME NULL,
+	created_at DATETIME NOT NULL
+)`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS uniq_api_key_hash ON api_key (hash)`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS api_key`,
+		},
+	},
+}
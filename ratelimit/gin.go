@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/ginroute"
+	"github.com/phantomnat/go-clean-architecture/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// responseError has the shape of the ResponseError of the handlers
+type responseError struct {
+	Message string `json:"message"`
+}
+
+// Limits returns the limit of the route, e.g. GET /articles/:id, ok is false when its requests are not limited
+type Limits func(method, route string) (limit Limit, ok bool)
+
+// Middleware limits the requests of every client to every route of the engine, the requests beyond the limit are
+// answered 429. The client is the API key or the subject of the principal of the request, otherwise its IP address
+// as told by proxies, so it must come after the authentication. The state of the bucket is sent in the RateLimit-*
+// headers.
+func Middleware(e *gin.Engine, store Store, limits Limits, proxies Proxies) gin.HandlerFunc {
+	routes := ginroute.NewResolver(e)
+
+	return func(c *gin.Context) {
+		route := routes.Route(c)
+		limit, ok := limits(c.Request.Method, route)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		client := clientKey(c, proxies)
+		res, err := store.Take(ctx, client+" "+c.Request.Method+" "+route, limit, time.Now())
+		if err != nil {
+			// the requests are served rather than all denied while the store fails
+			logging.FromContext(ctx).Error(err)
+			c.Next()
+			return
+		}
+
+		h := c.Writer.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(int(limit.burst())))
+		h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		h.Set("RateLimit-Reset", seconds(res.Reset))
+		if !res.Allowed {
+			h.Set("Retry-After", seconds(res.RetryAfter))
+			logging.FromContext(ctx).WithField("client", client).Info(ErrRateLimited)
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, responseError{Message: ErrRateLimited.Error()})
+			return
+		}
+		c.Next()
+	}
+}
+
+// clientKey tells the clients apart: by API key, by subject, then by IP address for the anonymous ones
+func clientKey(c *gin.Context, proxies Proxies) string {
+	if p, ok := domain.PrincipalFromContext(c.Request.Context()); ok {
+		if p.APIKeyID != 0 {
+			return "apikey:" + strconv.FormatInt(p.APIKeyID, 10)
+		}
+		return "subject:" + p.Subject
+	}
+	return "ip:" + proxies.ClientIP(c.Request)
+}
+
+// seconds formats d in whole seconds, rounded up so the client does not retry too early
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10)
+}
@@ -0,0 +1,94 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newRouter serves /articles limited to 2 requests a minute and /authors without limit,
+// the principal of the request is the subject of its X-Subject header
+func newRouter(store ratelimit.Store) *gin.Engine {
+	e := gin.New()
+	e.Use(func(c *gin.Context) {
+		if subject := c.GetHeader("X-Subject"); subject != "" {
+			ctx := domain.ContextWithPrincipal(c.Request.Context(), domain.Principal{Subject: subject})
+			c.Request = c.Request.WithContext(ctx)
+		}
+	})
+	e.Use(ratelimit.Middleware(e, store, func(method, route string) (ratelimit.Limit, bool) {
+		return ratelimit.Limit{Requests: 2, Period: time.Minute}, route == "/articles"
+	}, nil))
+	e.GET("/articles", func(c *gin.Context) { c.Status(http.StatusOK) })
+	e.GET("/authors", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return e
+}
+
+func serve(e *gin.Engine, path, subject string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if subject != "" {
+		req.Header.Set("X-Subject", subject)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware(t *testing.T) {
+	e := newRouter(ratelimit.NewMemoryStore())
+
+	rec := serve(e, "/articles", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", rec.Header().Get("RateLimit-Reset"))
+	assert.Empty(t, rec.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, serve(e, "/articles", "").Code)
+	rec = serve(e, "/articles", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"message":"rate limit exceeded, retry later"}`, rec.Body.String())
+
+	// the address of the connection is not replaced by a made up one
+	req := httptest.NewRequest(http.MethodGet, "/articles", nil)
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	// the authenticated clients are told apart from the anonymous ones of the same address
+	assert.Equal(t, http.StatusOK, serve(e, "/articles", "iman").Code)
+	// the routes without limit are left alone
+	rec = serve(e, "/authors", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+}
+
+// failingStore is a shared store which cannot be reached
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit ratelimit.Limit, now time.Time) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("connection refused")
+}
+
+func TestMiddlewareStoreError(t *testing.T) {
+	e := newRouter(failingStore{})
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, serve(e, "/articles", "").Code)
+	}
+}
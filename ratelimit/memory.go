@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is the time between the removals of the full buckets, a full bucket is the same as none
+const sweepInterval = time.Minute
+
+type memoryBucket struct {
+	bucket
+	// full is when the bucket is full again
+	full time.Time
+}
+
+type memoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+// NewMemoryStore will create the Store keeping the buckets in the process, the buckets of the clients which stopped
+// making requests are removed once they are full again
+func NewMemoryStore() Store {
+	return &memoryStore{
+		buckets: make(map[string]*memoryBucket),
+	}
+}
+
+func (s *memoryStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{bucket: bucket{tokens: limit.burst(), last: now}}
+		s.buckets[key] = b
+	}
+	res := b.take(limit, now)
+	b.full = now.Add(res.Reset)
+	return res, nil
+}
+
+// sweep removes the buckets full at now
+func (s *memoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
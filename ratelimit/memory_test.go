@@ -0,0 +1,58 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	s := ratelimit.NewMemoryStore()
+	// a token every 10s, up to 3 at once
+	limit := ratelimit.Limit{Requests: 6, Period: time.Minute, Burst: 3}
+	now := time.Now()
+
+	for i := 2; i >= 0; i-- {
+		res, err := s.Take(context.TODO(), "client", limit, now)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, i, res.Remaining)
+		assert.Equal(t, time.Duration(3-i)*10*time.Second, res.Reset)
+	}
+
+	res, err := s.Take(context.TODO(), "client", limit, now.Add(4*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, ratelimit.Result{Allowed: false, Remaining: 0, Reset: 26 * time.Second, RetryAfter: 6 * time.Second}, res)
+
+	// the other clients have their own bucket
+	res, err = s.Take(context.TODO(), "other", limit, now.Add(4*time.Second))
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	res, err = s.Take(context.TODO(), "client", limit, now.Add(10*time.Second))
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+
+	// a full bucket holds no more than the burst
+	res, err = s.Take(context.TODO(), "client", limit, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Remaining)
+}
+
+func TestMemoryStoreLoweredLimit(t *testing.T) {
+	s := ratelimit.NewMemoryStore()
+	now := time.Now()
+
+	_, err := s.Take(context.TODO(), "client", ratelimit.Limit{Requests: 100, Period: time.Minute}, now)
+	require.NoError(t, err)
+	res, err := s.Take(context.TODO(), "client", ratelimit.Limit{Requests: 1, Period: time.Minute}, now)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Proxies are the networks of the reverse proxies trusted to give the address of the client in X-Forwarded-For
+type Proxies []*net.IPNet
+
+// ParseProxies reads the proxies, each one an IP address or a CIDR network, e.g. 10.0.0.0/8
+func ParseProxies(list []string) (Proxies, error) {
+	proxies := make(Proxies, 0, len(list))
+	for _, s := range list {
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("%q is not an IP address or a CIDR network", s)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an IP address or a CIDR network", s)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+func (p Proxies) trusted(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, network := range p {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the address of the client of the request: the address of the connection, unless it is a trusted
+// proxy. Then it is the last address of X-Forwarded-For which is not a trusted proxy, the ones before it may be
+// made up by the client.
+func (p Proxies) ClientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if !p.trusted(ip) {
+		return ip
+	}
+
+	var forwarded []string
+	for _, h := range r.Header["X-Forwarded-For"] {
+		forwarded = append(forwarded, strings.Split(h, ",")...)
+	}
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(addr) == nil {
+			// the proxy appended something else than an address, nothing before it can be trusted
+			return ip
+		}
+		if !p.trusted(addr) {
+			return addr
+		}
+		ip = addr
+	}
+	return ip
+}
@@ -0,0 +1,46 @@
+package ratelimit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phantomnat/go-clean-architecture/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientIP(t *testing.T) {
+	proxies, err := ratelimit.ParseProxies([]string{"10.0.0.0/8", "192.168.1.10", "::1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded []string
+		expected  string
+	}{
+		{"direct", "203.0.113.7:4242", nil, "203.0.113.7"},
+		{"direct made up", "203.0.113.7:4242", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"proxied", "10.1.2.3:4242", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"proxied ipv6", "[::1]:4242", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"proxied made up", "10.1.2.3:4242", []string{"1.1.1.1, 198.51.100.1"}, "198.51.100.1"},
+		{"proxies chain", "10.1.2.3:4242", []string{"198.51.100.1", "192.168.1.10, 10.0.0.1"}, "198.51.100.1"},
+		{"proxied garbage", "10.1.2.3:4242", []string{"198.51.100.1, unknown"}, "10.1.2.3"},
+		{"proxied without header", "10.1.2.3:4242", nil, "10.1.2.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remote
+			for _, h := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", h)
+			}
+			assert.Equal(t, tt.expected, proxies.ClientIP(r))
+		})
+	}
+
+	_, err = ratelimit.ParseProxies([]string{"10.0.0.0/33"})
+	assert.EqualError(t, err, `"10.0.0.0/33" is not an IP address or a CIDR network`)
+}
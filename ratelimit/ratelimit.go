@@ -0,0 +1,79 @@
+// Package ratelimit limits the requests of every client with token buckets, the buckets are kept in a Store
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrRateLimited is the error of the requests beyond the limit
+var ErrRateLimited = errors.New("rate limit exceeded, retry later")
+
+// Limit lets a client make Requests every Period, up to Burst of them at once. Burst defaults to Requests,
+// Requests and Period must be greater than 0.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// burst returns the capacity of the bucket
+func (l Limit) burst() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.Requests)
+}
+
+// interval returns the time the bucket takes to get a token back
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+// Result is the state of the bucket once a request took its token, or was denied one
+type Result struct {
+	Allowed bool
+	// Remaining is the number of requests the client may still make at once
+	Remaining int
+	// Reset is the time until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed, 0 when the request is allowed
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets of the clients. NewMemoryStore keeps them in the process, each instance of the service
+// then has its own, a store shared by the instances, e.g. on Redis, makes the limits global.
+type Store interface {
+	// Take takes a token from the bucket of key for a request made at now, the bucket is created full
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// bucket is a token bucket, the tokens it got back since last are added when it is taken from
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take refills the bucket up to now and takes a token from it when it has one
+func (b *bucket) take(limit Limit, now time.Time) Result {
+	burst, interval := limit.burst(), float64(limit.interval())
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += float64(elapsed) / interval
+		b.last = now
+	}
+	// the limit may have been lowered since the last request
+	b.tokens = math.Min(b.tokens, burst)
+
+	var res Result
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration(math.Ceil((1 - b.tokens) * interval))
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = time.Duration(math.Ceil((burst - b.tokens) * interval))
+	return res
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/config"
+	"github.com/phantomnat/go-clean-architecture/config/env"
+
+	"github.com/sirupsen/logrus"
+)
+
+// contextTimeoutSetter is implemented by the usecases whose timeout can be changed while serving
+type contextTimeoutSetter interface {
+	SetContextTimeout(timeout time.Duration)
+}
+
+// liveConfig holds the configuration of the running server. A reload only applies the settings which are safe
+// to change while serving: the logging, the timeouts, the rate limits and the feature flags. The others need a restart.
+type liveConfig struct {
+	mu          sync.RWMutex
+	cfg         config.AppConfig
+	subscribers []func(config.AppConfig)
+}
+
+func newLiveConfig(cfg config.AppConfig) *liveConfig {
+	return &liveConfig{cfg: cfg}
+}
+
+// Get returns the current configuration
+func (l *liveConfig) Get() config.AppConfig {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.cfg
+}
+
+// Subscribe registers fn, it is given the configuration after every reload
+func (l *liveConfig) Subscribe(fn func(config.AppConfig)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.subscribers = append(l.subscribers, fn)
+}
+
+// Reload loads the settings again and applies their live part, an invalid configuration is ignored
+func (l *liveConfig) Reload(settings env.Config) {
+	next, err := config.Load(settings)
+	if err != nil {
+		logrus.Errorf("keeping the current configuration: %v", err)
+		return
+	}
+	l.apply(next)
+}
+
+func (l *liveConfig) apply(next config.AppConfig) {
+	l.mu.Lock()
+	prev := l.cfg
+	cfg := withLiveSettings(prev, next)
+	l.cfg = cfg
+	subscribers := append([]func(config.AppConfig){}, l.subscribers...)
+	l.mu.Unlock()
+
+	if !reflect.DeepEqual(withLiveSettings(next, prev), prev) {
+		logrus.Warn("configuration reloaded, the changes of the server, database, metrics, tracing, health and auth settings need a restart")
+	} else {
+		logrus.Info("configuration reloaded")
+	}
+	for _, fn := range subscribers {
+		fn(cfg)
+	}
+}
+
+// withLiveSettings returns cfg with the settings of live which can change while serving
+func withLiveSettings(cfg, live config.AppConfig) config.AppConfig {
+	cfg.Logging = live.Logging
+	cfg.Timeouts = live.Timeouts
+	cfg.RateLimit = live.RateLimit
+	cfg.Features = live.Features
+	return cfg
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/phantomnat/go-clean-architecture/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiveConfigApply(t *testing.T) {
+	cfg := config.Default()
+	live := newLiveConfig(cfg)
+
+	var applied []config.AppConfig
+	live.Subscribe(func(cfg config.AppConfig) {
+		applied = append(applied, cfg)
+	})
+
+	next := config.Default()
+	next.Logging.Level = "debug"
+	next.Timeouts.Usecase = 5 * time.Second
+	next.Features.Search = false
+	next.RateLimit.Enabled = true
+	next.Server.Addr = ":9090"
+	next.Database.Driver = config.DriverMemory
+	live.apply(next)
+
+	got := live.Get()
+	assert.Equal(t, "debug", got.Logging.Level)
+	assert.Equal(t, 5*time.Second, got.Timeouts.Usecase)
+	assert.False(t, got.Features.Search)
+	assert.True(t, got.RateLimit.Enabled)
+	// the server and the database only change on restart
+	assert.Equal(t, cfg.Server, got.Server)
+	assert.Equal(t, cfg.Database, got.Database)
+
+	assert.Equal(t, []config.AppConfig{got}, applied)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/phantomnat/go-clean-architecture/config"
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// runSeed implements `seed`, it fills the database with sample authors and articles
+func runSeed(cfg config.AppConfig, args []string) {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	numAuthors := fs.Int("authors", 3, "number of authors to create when there is none yet")
+	numArticles := fs.Int("articles", 10, "number of articles to create")
+	fs.Parse(args)
+
+	if cfg.Database.Driver == config.DriverMemory {
+		logrus.Fatal("the memory driver keeps nothing once the command exits, there is nothing to seed")
+	}
+	s := openStorage(cfg.Database, false)
+	defer s.close()
+
+	au, auu := newUsecases(s, false, cfg.Timeouts.Usecase)
+	authors, articles, err := seed(context.Background(), au, auu, *numAuthors, *numArticles)
+	fmt.Printf("seeded %d authors and %d articles\n", authors, articles)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+// seed creates the sample articles, spread over the existing authors. The authors are only created
+// when there is none, and the articles that already exist are skipped, so seeding twice is harmless.
+func seed(ctx context.Context, au domain.ArticleUsecase, auu domain.AuthorUsecase, numAuthors, numArticles int) (
+	createdAuthors int, createdArticles int, err error) {
+
+	authors, _, err := auu.Fetch(ctx, "", 100)
+	if err != nil {
+		return
+	}
+	if len(authors) == 0 {
+		for i := 1; i <= numAuthors; i++ {
+			author := domain.Author{Name: fmt.Sprintf("Author %d", i)}
+			if err = auu.Store(ctx, &author); err != nil {
+				return
+			}
+			authors = append(authors, author)
+			createdAuthors++
+		}
+	}
+	if len(authors) == 0 {
+		return
+	}
+
+	for i := 1; i <= numArticles; i++ {
+		author := authors[(i-1)%len(authors)]
+		ar := domain.Article{
+			Title:   fmt.Sprintf("Sample article %d", i),
+			Content: fmt.Sprintf("This is the sample article number %d, written by %s.", i, author.Name),
+			Author:  domain.Author{ID: author.ID},
+		}
+		err = au.Store(ctx, &ar)
+		if err == domain.ErrAlreadyExist {
+			continue
+		}
+		if err != nil {
+			return
+		}
+		createdArticles++
+	}
+	return createdAuthors, createdArticles, nil
+}
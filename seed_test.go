@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/phantomnat/go-clean-architecture/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeed(t *testing.T) {
+	s := openStorage(config.DatabaseConfig{Driver: config.DriverMemory}, false)
+	au, auu := newUsecases(s, false, config.Default().Timeouts.Usecase)
+
+	authors, articles, err := seed(context.TODO(), au, auu, 3, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 3, authors)
+	assert.Equal(t, 10, articles)
+
+	// seeding again reuses the authors and skips the existing articles
+	authors, articles, err = seed(context.TODO(), au, auu, 3, 12)
+	require.NoError(t, err)
+	assert.Equal(t, 0, authors)
+	assert.Equal(t, 2, articles)
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	apikeyHttp "github.com/phantomnat/go-clean-architecture/apikey/delivery/http"
+	apikeyUsecase "github.com/phantomnat/go-clean-architecture/apikey/usecase"
+	articleHttp "github.com/phantomnat/go-clean-architecture/article/delivery/http"
+	"github.com/phantomnat/go-clean-architecture/auth"
+	authorHttp "github.com/phantomnat/go-clean-architecture/author/delivery/http"
+	"github.com/phantomnat/go-clean-architecture/config"
+	"github.com/phantomnat/go-clean-architecture/config/env"
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/health"
+	healthHttp "github.com/phantomnat/go-clean-architecture/health/delivery/http"
+	"github.com/phantomnat/go-clean-architecture/logging"
+	"github.com/phantomnat/go-clean-architecture/metrics"
+	"github.com/phantomnat/go-clean-architecture/ratelimit"
+	"github.com/phantomnat/go-clean-architecture/tracing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// newServer creates the HTTP server of given handler from the server.* settings
+func newServer(cfg config.ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:         cfg.Addr,
+		Handler:      handler,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+	}
+}
+
+// featureGate answers 404 to the requests of the route while its feature is disabled, as if it was not registered
+func featureGate(method, path string, enabled func() bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == method && c.Request.URL.Path == path && !enabled() {
+			c.AbortWithStatus(http.StatusNotFound)
+		}
+	}
+}
+
+// warnUnknownRoutes warns about the limits of the routes which are not registered in the engine, e.g. a typo,
+// or the path instead of the path template of the route
+func warnUnknownRoutes(e *gin.Engine, cfg config.RateLimitConfig) {
+	routes := make(map[string]bool)
+	for _, r := range e.Routes() {
+		routes[r.Method+" "+r.Path] = true
+	}
+	for route := range cfg.Routes {
+		if !routes[route] {
+			logrus.Warnf("rate_limit.routes: %q is not a route of the service, its limit is never applied", route)
+		}
+	}
+}
+
+// runServe implements `serve`, it starts the HTTP server and drains it on SIGTERM or SIGINT
+func runServe(settings env.Config, cfg config.AppConfig, args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	fs.Parse(args)
+
+	if cfg.Debug {
+		fmt.Println("service run on DEBUG mode")
+	}
+
+	var tp *sdktrace.TracerProvider
+	if cfg.Tracing.Enabled {
+		tp = setupTracing(cfg.Tracing)
+	}
+
+	if cfg.Database.Driver == config.DriverMemory {
+		logrus.Warn("using the in-memory storage, every data is lost when the service stops")
+	}
+	s := openStorage(cfg.Database, cfg.Tracing.Enabled)
+
+	var m *metrics.Metrics
+	if cfg.Metrics.Enabled {
+		m = metrics.NewMetrics()
+		if s.dbConn != nil {
+			if err := m.RegisterDB(cfg.Database.Driver, s.dbConn); err != nil {
+				logrus.Fatal(err)
+			}
+		}
+		s.articleRepo = metrics.NewArticleRepository(s.articleRepo, m)
+	}
+	if tp != nil {
+		s.articleRepo = tracing.NewArticleRepository(s.articleRepo, tp)
+		s.authorRepo = tracing.NewAuthorRepository(s.authorRepo, tp)
+	}
+
+	// without authentication every caller is anonymous, the policies of the roles would deny them every change
+	au, auu := newUsecases(s, cfg.Auth.Required(), cfg.Timeouts.Usecase)
+	ku := apikeyUsecase.NewAPIKeyUseCase(s.apiKeyRepo, s.authorRepo, cfg.Timeouts.Usecase)
+	live := newLiveConfig(cfg)
+	live.Subscribe(func(cfg config.AppConfig) {
+		if err := logging.Configure(logrus.StandardLogger(), cfg.Logging.Format, cfg.Logging.Level); err != nil {
+			logrus.Error(err)
+		}
+	})
+	for _, u := range []interface{}{au, auu, ku} {
+		if u, ok := u.(contextTimeoutSetter); ok {
+			live.Subscribe(func(cfg config.AppConfig) {
+				u.SetContextTimeout(cfg.Timeouts.Usecase)
+			})
+		}
+	}
+	if m != nil {
+		au = metrics.NewArticleUsecase(au, m)
+	}
+	if tp != nil {
+		au = tracing.NewArticleUsecase(au, tp)
+	}
+
+	hc := health.NewHealth(cfg.Health.Timeout)
+	if s.dbConn != nil {
+		hc.Register("database", health.PingCheck(s.dbConn))
+	}
+
+	router := gin.New()
+	router.Use(gin.Recovery())
+	if tp != nil {
+		router.Use(tracing.Middleware(router, tp))
+	}
+	// after the tracing, the logger of the request is tagged with the trace id
+	router.Use(logging.Middleware(logrus.StandardLogger()))
+	if m != nil {
+		router.Use(m.Middleware(router))
+		router.GET("/metrics", gin.WrapH(m.Handler()))
+	}
+	if cfg.Auth.APIKeys {
+		// the API keys first, the bearer tokens are not required from the requests they authenticate
+		router.Use(apikeyHttp.Middleware(ku))
+	}
+	if cfg.Auth.Enabled {
+		router.Use(auth.Middleware(newAuthenticator(cfg.Auth, s.authorRepo)))
+	} else if cfg.Auth.APIKeys {
+		router.Use(auth.RequireCredentials())
+	}
+	router.Use(featureGate(http.MethodGet, "/articles/search", func() bool {
+		return live.Get().Features.Search
+	}))
+	healthHttp.NewHealthHttpHandler(router, hc)
+	// after the probes, they are never limited, and after the authentication, the clients are told apart by it
+	// validated with the configuration
+	proxies, _ := ratelimit.ParseProxies(cfg.Server.TrustedProxies)
+	router.Use(ratelimit.Middleware(router, ratelimit.NewMemoryStore(), func(method, route string) (ratelimit.Limit, bool) {
+		limit, ok := live.Get().RateLimit.Limit(method, route)
+		return ratelimit.Limit{Requests: limit.Requests, Period: limit.Period, Burst: limit.Burst}, ok
+	}, proxies))
+	av := articleHttp.NewArticleValidator(auu)
+	articleHttp.NewArticleHttpHandler(router, au, av)
+	authorHttp.NewAuthorHttpHandler(router, auu)
+	if cfg.Auth.APIKeys {
+		apikeyHttp.NewAPIKeyHttpHandler(router, ku)
+	}
+	warnUnknownRoutes(router, cfg.RateLimit)
+	live.Subscribe(func(cfg config.AppConfig) {
+		warnUnknownRoutes(router, cfg.RateLimit)
+	})
+
+	srv := newServer(cfg.Server, router)
+	ln, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	stop := make(chan os.Signal, 1)
+	go func() {
+		sig := <-signals
+		// fail the readiness first and keep serving while the load balancer stops sending new requests
+		hc.ShutDown()
+		delay := cfg.Server.ShutdownDelay
+		if delay > 0 {
+			logrus.Infof("received %s, not ready anymore, shutting down in %s", sig, delay)
+			time.Sleep(delay)
+		}
+		stop <- sig
+	}()
+
+	if cfg.Server.HotReload {
+		settings.Subscribe(func() {
+			live.Reload(settings)
+		})
+		stopWatch, err := settings.Watch(func(err error) {
+			logrus.Errorf("watching the config files: %v", err)
+		})
+		if err != nil {
+			logrus.Fatal(err)
+		}
+		defer stopWatch()
+	}
+
+	logrus.Infof("listening on %s", ln.Addr())
+	err = serve(srv, ln, stop, cfg.Server.ShutdownTimeout)
+	// the database is only closed once the in-flight requests are done with it
+	s.close()
+	if tp != nil {
+		shutdownTracing(tp, cfg.Server.ShutdownTimeout)
+	}
+	if err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+// newAuthenticator creates the authenticator of the auth.* settings, the authors of the tokens are read from authors
+func newAuthenticator(cfg config.AuthConfig, authors domain.AuthorRepository) *auth.Authenticator {
+	a, err := auth.NewAuthenticator(auth.Config{
+		Secret:    cfg.Secret,
+		PublicKey: cfg.PublicKey,
+		JWKSFile:  cfg.JWKSFile,
+		Issuer:    cfg.Issuer,
+		Audience:  cfg.Audience,
+	}, authors)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	return a
+}
+
+// setupTracing registers the tracer provider of the tracing.* settings
+func setupTracing(cfg config.TracingConfig) *sdktrace.TracerProvider {
+	tp, err := tracing.Setup(tracing.Config{
+		ServiceName: cfg.ServiceName,
+		Exporter:    cfg.Exporter,
+		Endpoint:    cfg.Endpoint,
+		File:        cfg.File,
+	})
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	return tp
+}
+
+// shutdownTracing exports the spans still buffered by the provider within timeout
+func shutdownTracing(tp *sdktrace.TracerProvider, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := tp.Shutdown(ctx); err != nil {
+		logrus.Error(err)
+	}
+}
+
+// serve accepts the connections of ln until a signal is received from stop, then stops accepting
+// new connections and waits at most grace for the in-flight requests to complete
+func serve(srv *http.Server, ln net.Listener, stop <-chan os.Signal, grace time.Duration) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case sig := <-stop:
+		logrus.Infof("received %s, shutting down within %s", sig, grace)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		// the grace period is over, cut off the remaining connections
+		srv.Close()
+		return err
+	}
+
+	if err := <-errs; err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
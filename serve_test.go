@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeDrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("done"))
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	stop := make(chan os.Signal, 1)
+	served := make(chan error, 1)
+	go func() {
+		served <- serve(&http.Server{Handler: handler}, ln, stop, time.Second)
+	}()
+
+	type response struct {
+		body string
+		err  error
+	}
+	responses := make(chan response, 1)
+	go func() {
+		res, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			responses <- response{err: err}
+			return
+		}
+		defer res.Body.Close()
+		body, err := ioutil.ReadAll(res.Body)
+		responses <- response{body: string(body), err: err}
+	}()
+
+	<-started
+	stop <- syscall.SIGTERM
+
+	res := <-responses
+	require.NoError(t, res.err)
+	assert.Equal(t, "done", res.body)
+	assert.NoError(t, <-served)
+
+	// no new connection is accepted once the server is shut down
+	_, err = net.Dial("tcp", ln.Addr().String())
+	assert.Error(t, err)
+}
+
+func TestServeGracePeriodExceeded(t *testing.T) {
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	stop := make(chan os.Signal, 1)
+	served := make(chan error, 1)
+	go func() {
+		served <- serve(&http.Server{Handler: handler}, ln, stop, 50*time.Millisecond)
+	}()
+
+	go http.Get("http://" + ln.Addr().String())
+	<-started
+	stop <- syscall.SIGTERM
+
+	assert.Equal(t, context.DeadlineExceeded, <-served)
+}
+
+func TestFeatureGate(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		enabled := enabled
+		e := gin.New()
+		e.Use(featureGate(http.MethodGet, "/articles/search", func() bool { return enabled }))
+		e.GET("/articles/search", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+		e.GET("/articles", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/articles/search?q=go", nil))
+		if enabled {
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+		} else {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/articles", nil))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	}
+}
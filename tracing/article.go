@@ -0,0 +1,146 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// end records the error of the call in the span before ending it.
+// The errors of the caller, e.g. a missing article, do not fail the span.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		switch err {
+		case domain.ErrNotFound, domain.ErrAlreadyExist, domain.ErrBadParamInput, domain.ErrForbidden:
+		default:
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	span.End()
+}
+
+type articleUsecase struct {
+	next   domain.ArticleUsecase
+	tracer trace.Tracer
+}
+
+// NewArticleUsecase will decorate the usecase to trace every call
+func NewArticleUsecase(next domain.ArticleUsecase, tp trace.TracerProvider) domain.ArticleUsecase {
+	return &articleUsecase{
+		next:   next,
+		tracer: tp.Tracer(instrumentationName),
+	}
+}
+
+func (a *articleUsecase) start(ctx context.Context, method string) (context.Context, trace.Span) {
+	return a.tracer.Start(ctx, "ArticleUsecase."+method)
+}
+
+func (a *articleUsecase) Fetch(ctx context.Context, filter domain.ArticleFilter, cursor string, num int64,
+	direction domain.Direction, order domain.SortOrder) (res []domain.Article, nextCursor string, prevCursor string, err error) {
+	ctx, span := a.start(ctx, "Fetch")
+	defer func() { end(span, err) }()
+	return a.next.Fetch(ctx, filter, cursor, num, direction, order)
+}
+
+func (a *articleUsecase) GetByID(ctx context.Context, id int64) (res domain.Article, err error) {
+	ctx, span := a.start(ctx, "GetByID")
+	defer func() { end(span, err) }()
+	return a.next.GetByID(ctx, id)
+}
+
+func (a *articleUsecase) Update(ctx context.Context, ar *domain.Article) (err error) {
+	ctx, span := a.start(ctx, "Update")
+	defer func() { end(span, err) }()
+	return a.next.Update(ctx, ar)
+}
+
+func (a *articleUsecase) GetByTitle(ctx context.Context, title string) (res domain.Article, err error) {
+	ctx, span := a.start(ctx, "GetByTitle")
+	defer func() { end(span, err) }()
+	return a.next.GetByTitle(ctx, title)
+}
+
+func (a *articleUsecase) Search(ctx context.Context, query string, cursor string, num int64) (
+	res []domain.ArticleSearchResult, nextCursor string, err error) {
+	ctx, span := a.start(ctx, "Search")
+	defer func() { end(span, err) }()
+	return a.next.Search(ctx, query, cursor, num)
+}
+
+func (a *articleUsecase) Store(ctx context.Context, ar *domain.Article) (err error) {
+	ctx, span := a.start(ctx, "Store")
+	defer func() { end(span, err) }()
+	return a.next.Store(ctx, ar)
+}
+
+func (a *articleUsecase) Delete(ctx context.Context, id int64) (err error) {
+	ctx, span := a.start(ctx, "Delete")
+	defer func() { end(span, err) }()
+	return a.next.Delete(ctx, id)
+}
+
+type articleRepository struct {
+	next   domain.ArticleRepository
+	tracer trace.Tracer
+}
+
+// NewArticleRepository will decorate the repository to trace every call
+func NewArticleRepository(next domain.ArticleRepository, tp trace.TracerProvider) domain.ArticleRepository {
+	return &articleRepository{
+		next:   next,
+		tracer: tp.Tracer(instrumentationName),
+	}
+}
+
+func (a *articleRepository) start(ctx context.Context, method string) (context.Context, trace.Span) {
+	return a.tracer.Start(ctx, "ArticleRepository."+method)
+}
+
+func (a *articleRepository) Fetch(ctx context.Context, filter domain.ArticleFilter, cursor string, num int64,
+	direction domain.Direction, order domain.SortOrder) (res []domain.Article, nextCursor string, prevCursor string, err error) {
+	ctx, span := a.start(ctx, "Fetch")
+	defer func() { end(span, err) }()
+	return a.next.Fetch(ctx, filter, cursor, num, direction, order)
+}
+
+func (a *articleRepository) GetByID(ctx context.Context, id int64) (res domain.Article, err error) {
+	ctx, span := a.start(ctx, "GetByID")
+	defer func() { end(span, err) }()
+	return a.next.GetByID(ctx, id)
+}
+
+func (a *articleRepository) GetByTitle(ctx context.Context, title string) (res domain.Article, err error) {
+	ctx, span := a.start(ctx, "GetByTitle")
+	defer func() { end(span, err) }()
+	return a.next.GetByTitle(ctx, title)
+}
+
+func (a *articleRepository) Search(ctx context.Context, query string, cursor string, num int64) (
+	res []domain.ArticleSearchResult, nextCursor string, err error) {
+	ctx, span := a.start(ctx, "Search")
+	defer func() { end(span, err) }()
+	return a.next.Search(ctx, query, cursor, num)
+}
+
+func (a *articleRepository) Update(ctx context.Context, ar *domain.Article) (err error) {
+	ctx, span := a.start(ctx, "Update")
+	defer func() { end(span, err) }()
+	return a.next.Update(ctx, ar)
+}
+
+func (a *articleRepository) Store(ctx context.Context, ar *domain.Article) (err error) {
+	ctx, span := a.start(ctx, "Store")
+	defer func() { end(span, err) }()
+	return a.next.Store(ctx, ar)
+}
+
+func (a *articleRepository) Delete(ctx context.Context, id int64) (err error) {
+	ctx, span := a.start(ctx, "Delete")
+	defer func() { end(span, err) }()
+	return a.next.Delete(ctx, id)
+}
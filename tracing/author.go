@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type authorRepository struct {
+	next   domain.AuthorRepository
+	tracer trace.Tracer
+}
+
+// NewAuthorRepository will decorate the repository to trace every call
+func NewAuthorRepository(next domain.AuthorRepository, tp trace.TracerProvider) domain.AuthorRepository {
+	return &authorRepository{
+		next:   next,
+		tracer: tp.Tracer(instrumentationName),
+	}
+}
+
+func (a *authorRepository) start(ctx context.Context, method string) (context.Context, trace.Span) {
+	return a.tracer.Start(ctx, "AuthorRepository."+method)
+}
+
+func (a *authorRepository) Fetch(ctx context.Context, cursor string, num int64) (res []domain.Author, nextCursor string, err error) {
+	ctx, span := a.start(ctx, "Fetch")
+	defer func() { end(span, err) }()
+	return a.next.Fetch(ctx, cursor, num)
+}
+
+func (a *authorRepository) GetByID(ctx context.Context, id int64) (res domain.Author, err error) {
+	ctx, span := a.start(ctx, "GetByID")
+	defer func() { end(span, err) }()
+	return a.next.GetByID(ctx, id)
+}
+
+func (a *authorRepository) GetByIDs(ctx context.Context, ids []int64) (res map[int64]domain.Author, err error) {
+	ctx, span := a.start(ctx, "GetByIDs")
+	defer func() { end(span, err) }()
+	return a.next.GetByIDs(ctx, ids)
+}
+
+func (a *authorRepository) Update(ctx context.Context, au *domain.Author) (err error) {
+	ctx, span := a.start(ctx, "Update")
+	defer func() { end(span, err) }()
+	return a.next.Update(ctx, au)
+}
+
+func (a *authorRepository) Store(ctx context.Context, au *domain.Author) (err error) {
+	ctx, span := a.start(ctx, "Store")
+	defer func() { end(span, err) }()
+	return a.next.Store(ctx, au)
+}
+
+func (a *authorRepository) Delete(ctx context.Context, id int64) (err error) {
+	ctx, span := a.start(ctx, "Delete")
+	defer func() { end(span, err) }()
+	return a.next.Delete(ctx, id)
+}
@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/phantomnat/go-clean-architecture/ginroute"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware starts a server span for every request of the engine's routes, the trace of the traceparent
+// header is continued. The span is put in the context of the request, the handlers pass it on to the usecases.
+func Middleware(e *gin.Engine, tp trace.TracerProvider) gin.HandlerFunc {
+	routes := ginroute.NewResolver(e)
+	tracer := tp.Tracer(instrumentationName)
+
+	return func(c *gin.Context) {
+		ctx := propagator.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		route := routes.Route(c)
+		ctx, span := tracer.Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Request.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(c.Request.URL.Path),
+			),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
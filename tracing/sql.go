@@ -0,0 +1,233 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OpenDB opens the database like sql.Open does, every query run on the returned pool is traced by a
+// client span child of the span of its context. The repositories need no change, they are given the pool.
+func OpenDB(driverName, dsn string, tp trace.TracerProvider) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	d := db.Driver()
+	// the pool was only opened to look the driver up, it has no connection yet
+	db.Close()
+
+	var connector driver.Connector
+	if dc, ok := d.(driver.DriverContext); ok {
+		connector, err = dc.OpenConnector(dsn)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		connector = dsnConnector{dsn: dsn, driver: d}
+	}
+
+	return sql.OpenDB(&tracedConnector{
+		Connector: connector,
+		tracer: &sqlTracer{
+			tracer: tp.Tracer(instrumentationName),
+			system: semconv.DBSystemKey.String(dbSystem(driverName)),
+		},
+	}), nil
+}
+
+// dbSystem maps the name of the driver to the db.system attribute
+func dbSystem(driverName string) string {
+	if driverName == "sqlite3" {
+		return "sqlite"
+	}
+	return driverName
+}
+
+// sqlTracer records the spans of the queries
+type sqlTracer struct {
+	tracer trace.Tracer
+	system attribute.KeyValue
+}
+
+// trace records the span of a query started at start. The span is only created once the driver ran the
+// query, the driver may skip it and fall back to a prepared statement, which is then traced instead.
+func (t *sqlTracer) trace(ctx context.Context, query string, start time.Time, err error) {
+	_, span := t.tracer.Start(ctx, spanName(query),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(t.system, semconv.DBStatement(query)),
+	)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// spanName is the operation of the query, e.g. SELECT
+func spanName(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "query"
+	}
+	return strings.ToUpper(fields[0])
+}
+
+// dsnConnector opens the connections of the drivers which do not implement driver.DriverContext
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type tracedConnector struct {
+	driver.Connector
+	tracer *sqlTracer
+}
+
+func (c *tracedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{Conn: conn, tracer: c.tracer}, nil
+}
+
+// tracedConn forwards to the connection of the driver, the optional interfaces the driver does not
+// implement answer like database/sql does without them
+type tracedConn struct {
+	driver.Conn
+	tracer *sqlTracer
+}
+
+func (c *tracedConn) Prepare(query string) (driver.Stmt, error) {
+	stmt, err := c.Conn.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedStmt{Stmt: stmt, conn: c.Conn, query: query, tracer: c.tracer}, nil
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	cp, ok := c.Conn.(driver.ConnPrepareContext)
+	if !ok {
+		return c.Prepare(query)
+	}
+	stmt, err := cp.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedStmt{Stmt: stmt, conn: c.Conn, query: query, tracer: c.tracer}, nil
+}
+
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if cb, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return cb.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := q.QueryContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		c.tracer.trace(ctx, query, start, err)
+	}
+	return rows, err
+}
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	res, err := e.ExecContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		c.tracer.trace(ctx, query, start, err)
+	}
+	return res, err
+}
+
+func (c *tracedConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+type tracedStmt struct {
+	driver.Stmt
+	conn   driver.Conn
+	query  string
+	tracer *sqlTracer
+}
+
+func (s *tracedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (res driver.Result, err error) {
+	defer func(start time.Time) { s.tracer.trace(ctx, s.query, start, err) }(time.Now())
+	if e, ok := s.Stmt.(driver.StmtExecContext); ok {
+		return e.ExecContext(ctx, args)
+	}
+	return s.Stmt.Exec(values(args))
+}
+
+func (s *tracedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (rows driver.Rows, err error) {
+	defer func(start time.Time) { s.tracer.trace(ctx, s.query, start, err) }(time.Now())
+	if q, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		return q.QueryContext(ctx, args)
+	}
+	return s.Stmt.Query(values(args))
+}
+
+func (s *tracedStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	// database/sql only asks the statement once it implements the interface, ask the connection instead
+	if n, ok := s.conn.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// values drops the names of the arguments for the drivers without context support
+func values(args []driver.NamedValue) []driver.Value {
+	res := make([]driver.Value, len(args))
+	for i, arg := range args {
+		res[i] = arg.Value
+	}
+	return res
+}
@@ -0,0 +1,125 @@
+// Package tracing traces the requests with OpenTelemetry, from the HTTP server down to the SQL queries
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// instrumentationName is the name of the tracers of the service
+const instrumentationName = "github.com/phantomnat/go-clean-architecture"
+
+// Exporters of the finished spans
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+// propagator reads and writes the W3C trace context and baggage headers
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Config of the tracer provider
+type Config struct {
+	ServiceName string
+	// Exporter is one of ExporterOTLP, ExporterStdout or ExporterFile
+	Exporter string
+	// Endpoint is the URL of the OTLP/HTTP traces receiver, e.g. http://localhost:4318/v1/traces
+	Endpoint string
+	// File is the path the file exporter appends the spans to
+	File string
+}
+
+// NewTracerProvider will create the provider exporting the spans of the service as configured
+func NewTracerProvider(cfg Config) (*sdktrace.TracerProvider, error) {
+	exporter, err := newExporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res := resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName))
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	), nil
+}
+
+// Setup will create the tracer provider and register it, along with the propagator, as the global ones
+func Setup(cfg Config) (*sdktrace.TracerProvider, error) {
+	tp, err := NewTracerProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagator)
+	return tp, nil
+}
+
+func newExporter(cfg Config) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case ExporterOTLP:
+		if cfg.Endpoint == "" {
+			return nil, fmt.Errorf("tracing: the otlp exporter needs an endpoint")
+		}
+		return newOTLPExporter(cfg.Endpoint)
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterFile:
+		if cfg.File == "" {
+			return nil, fmt.Errorf("tracing: the file exporter needs a file")
+		}
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &closingExporter{SpanExporter: exporter, closer: f}, nil
+	default:
+		return nil, fmt.Errorf("tracing: unsupported exporter: %q", cfg.Exporter)
+	}
+}
+
+// newOTLPExporter will create the exporter posting the spans to endpoint, the traces URL of the OTLP/HTTP receiver
+func newOTLPExporter(endpoint string) (sdktrace.SpanExporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: invalid otlp endpoint: %v", err)
+	}
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(u.Host)}
+	if u.Path != "" {
+		opts = append(opts, otlptracehttp.WithURLPath(u.Path))
+	}
+	if u.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	return otlptracehttp.New(context.Background(), opts...)
+}
+
+// closingExporter closes the file the spans are written to on shutdown
+type closingExporter struct {
+	sdktrace.SpanExporter
+	closer io.Closer
+}
+
+func (e *closingExporter) Shutdown(ctx context.Context) error {
+	err := e.SpanExporter.Shutdown(ctx)
+	if cerr := e.closer.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
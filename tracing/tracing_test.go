@@ -0,0 +1,223 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phantomnat/go-clean-architecture/domain"
+	"github.com/phantomnat/go-clean-architecture/domain/mocks"
+	"github.com/phantomnat/go-clean-architecture/tracing"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func newProvider() (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	sr := tracetest.NewSpanRecorder()
+	return sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)), sr
+}
+
+// spanNamed returns the ended span of given name
+func spanNamed(t *testing.T, sr *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	for _, s := range sr.Ended() {
+		if s.Name() == name {
+			return s
+		}
+	}
+	require.FailNow(t, "missing span", name)
+	return nil
+}
+
+func attributeOf(s sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range s.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestMiddleware(t *testing.T) {
+	tp, sr := newProvider()
+	mockUCase := new(mocks.ArticleUsecase)
+	mockUCase.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1}, nil)
+	au := tracing.NewArticleUsecase(mockUCase, tp)
+
+	e := gin.New()
+	e.Use(tracing.Middleware(e, tp))
+	e.GET("/articles/:id", func(c *gin.Context) {
+		if _, err := au.GetByID(c.Request.Context(), 1); err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	server := spanNamed(t, sr, "GET /articles/:id")
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+	assert.Equal(t, "/articles/:id", attributeOf(server, "http.route").AsString())
+	assert.Equal(t, int64(http.StatusNoContent), attributeOf(server, "http.response.status_code").AsInt64())
+
+	usecase := spanNamed(t, sr, "ArticleUsecase.GetByID")
+	assert.Equal(t, server.SpanContext().SpanID(), usecase.Parent().SpanID())
+	assert.Equal(t, codes.Unset, usecase.Status().Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestArticleRepositoryError(t *testing.T) {
+	tp, sr := newProvider()
+	mockRepo := new(mocks.ArticleRepository)
+	mockRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{}, domain.ErrNotFound)
+	mockRepo.On("Delete", mock.Anything, int64(1)).Return(errors.New("Unexpected"))
+	repo := tracing.NewArticleRepository(mockRepo, tp)
+
+	_, err := repo.GetByID(context.TODO(), 1)
+	assert.Equal(t, domain.ErrNotFound, err)
+	err = repo.Delete(context.TODO(), 1)
+	assert.Error(t, err)
+
+	// a missing article is the caller's error, it does not fail the span
+	notFound := spanNamed(t, sr, "ArticleRepository.GetByID")
+	assert.Equal(t, codes.Unset, notFound.Status().Code)
+	assert.Len(t, notFound.Events(), 1)
+
+	failed := spanNamed(t, sr, "ArticleRepository.Delete")
+	assert.Equal(t, codes.Error, failed.Status().Code)
+	assert.Equal(t, "Unexpected", failed.Status().Description)
+}
+
+func TestOpenDB(t *testing.T) {
+	tp, sr := newProvider()
+	db, err := tracing.OpenDB("sqlite3", ":memory:", tp)
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	ctx, parent := tp.Tracer("test").Start(context.TODO(), "parent")
+	_, err = db.ExecContext(ctx, "CREATE TABLE author (id INTEGER PRIMARY KEY, name TEXT)")
+	require.NoError(t, err)
+
+	stmt, err := db.PrepareContext(ctx, "INSERT INTO author (name) VALUES (?)")
+	require.NoError(t, err)
+	_, err = stmt.ExecContext(ctx, "Iman Tumorang")
+	require.NoError(t, err)
+	stmt.Close()
+
+	var name string
+	err = db.QueryRowContext(ctx, "SELECT name FROM author WHERE id = ?", 1).Scan(&name)
+	require.NoError(t, err)
+	assert.Equal(t, "Iman Tumorang", name)
+
+	_, err = db.ExecContext(ctx, "SELECT * FROM missing")
+	assert.Error(t, err)
+	parent.End()
+
+	for _, name := range []string{"CREATE", "INSERT", "SELECT"} {
+		s := spanNamed(t, sr, name)
+		assert.Equal(t, trace.SpanKindClient, s.SpanKind())
+		assert.Equal(t, parent.SpanContext().SpanID(), s.Parent().SpanID())
+		assert.Equal(t, "sqlite", attributeOf(s, "db.system").AsString())
+	}
+	assert.Equal(t, "INSERT INTO author (name) VALUES (?)", attributeOf(spanNamed(t, sr, "INSERT"), "db.statement").AsString())
+
+	var failed int
+	for _, s := range sr.Ended() {
+		if s.Status().Code == codes.Error {
+			failed++
+			assert.Equal(t, "SELECT * FROM missing", attributeOf(s, "db.statement").AsString())
+		}
+	}
+	assert.Equal(t, 1, failed)
+}
+
+func TestOTLPExporter(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		bodies <- body
+	}))
+	defer receiver.Close()
+
+	tp, err := tracing.NewTracerProvider(tracing.Config{
+		ServiceName: "article",
+		Exporter:    tracing.ExporterOTLP,
+		Endpoint:    receiver.URL + "/v1/traces",
+	})
+	require.NoError(t, err)
+	_, span := tp.Tracer("test").Start(context.TODO(), "query",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.Int("rows", 3)))
+	span.SetStatus(codes.Error, "boom")
+	span.End()
+	require.NoError(t, tp.Shutdown(context.TODO()))
+
+	var req coltracepb.ExportTraceServiceRequest
+	require.NoError(t, proto.Unmarshal(<-bodies, &req))
+	require.Len(t, req.ResourceSpans, 1)
+	require.Len(t, req.ResourceSpans[0].ScopeSpans, 1)
+	scope := req.ResourceSpans[0].ScopeSpans[0]
+	assert.Equal(t, "test", scope.Scope.Name)
+	require.Len(t, scope.Spans, 1)
+
+	s := scope.Spans[0]
+	traceID := span.SpanContext().TraceID()
+	spanID := span.SpanContext().SpanID()
+	assert.Equal(t, traceID[:], s.TraceId)
+	assert.Equal(t, spanID[:], s.SpanId)
+	assert.Equal(t, "query", s.Name)
+	assert.Equal(t, tracepb.Span_SPAN_KIND_CLIENT, s.Kind)
+	assert.Equal(t, tracepb.Status_STATUS_CODE_ERROR, s.Status.Code)
+	assert.Equal(t, "boom", s.Status.Message)
+	require.Len(t, s.Attributes, 1)
+	assert.Equal(t, "rows", s.Attributes[0].Key)
+	assert.Equal(t, int64(3), s.Attributes[0].Value.GetIntValue())
+}
+
+func TestOTLPExporterFailure(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer receiver.Close()
+
+	tp, err := tracing.NewTracerProvider(tracing.Config{
+		ServiceName: "article",
+		Exporter:    tracing.ExporterOTLP,
+		Endpoint:    receiver.URL + "/v1/traces",
+	})
+	require.NoError(t, err)
+	_, span := tp.Tracer("test").Start(context.TODO(), "query")
+	span.End()
+
+	assert.Error(t, tp.ForceFlush(context.TODO()))
+	tp.Shutdown(context.TODO())
+}